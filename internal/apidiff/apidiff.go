@@ -0,0 +1,391 @@
+package apidiff
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// File is a Go file before and after a change. Old is nil for added files
+// and New is nil for deleted ones.
+type File struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// Op is what happened to an exported identifier
+type Op string
+
+const (
+	Added   Op = "added"
+	Removed Op = "removed"
+	Changed Op = "changed"
+)
+
+// Change is a difference in the exported API of a package
+type Change struct {
+	// Dir is the directory of the package, relative to the repository root
+	Dir     string
+	Package string
+	// Name is the identifier, qualified by its type for methods and fields
+	Name string
+	// Kind is func, method, type, field, embedded, const or var
+	Kind string
+	Op   Op
+	// Old and New are the declarations without parameter names, empty on the
+	// side where the identifier does not exist
+	Old      string
+	New      string
+	Breaking bool
+}
+
+func (c Change) String() string {
+	var s string
+	switch c.Op {
+	case Added:
+		s = fmt.Sprintf("added %s %s.%s (%s)", c.Kind, c.Package, c.Name, c.New)
+	case Removed:
+		s = fmt.Sprintf("removed %s %s.%s (%s)", c.Kind, c.Package, c.Name, c.Old)
+	default:
+		s = fmt.Sprintf("changed %s %s.%s from %s to %s", c.Kind, c.Package, c.Name, c.Old, c.New)
+	}
+
+	if c.Breaking {
+		return "BREAKING: " + s
+	}
+	return s
+}
+
+// HasBreaking reports whether any of the changes breaks the API
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// decl is an exported declaration. Members of a type are keyed "Type.Name"
+// and refer to it with parent.
+type decl struct {
+	kind   string
+	sig    string
+	parent string
+	// required is set for the methods and embedded interfaces of an
+	// interface, which implementations must add
+	required bool
+}
+
+type pkg struct {
+	name     string
+	old, new map[string]decl
+}
+
+// Compare diffs the exported API declared in files, grouping them by package
+// directory so that declarations moved between the files of a package are
+// not reported. Test files, main packages, internal packages and files that
+// do not parse on either side are skipped.
+func Compare(files []File) []Change {
+	pkgs := map[string]*pkg{}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Path, ".go") || strings.HasSuffix(f.Path, "_test.go") || isInternal(f.Path) {
+			continue
+		}
+
+		oldFile, ok := parse(f.Path, f.Old)
+		if !ok {
+			continue
+		}
+		newFile, ok := parse(f.Path, f.New)
+		if !ok {
+			continue
+		}
+
+		dir := path.Dir(f.Path)
+		p := pkgs[dir]
+		if p == nil {
+			p = &pkg{old: map[string]decl{}, new: map[string]decl{}}
+			pkgs[dir] = p
+		}
+		if oldFile != nil {
+			p.name = oldFile.Name.Name
+			collect(oldFile, p.old)
+		}
+		if newFile != nil {
+			p.name = newFile.Name.Name
+			collect(newFile, p.new)
+		}
+	}
+
+	var changes []Change
+	for dir, p := range pkgs {
+		if p.name == "main" {
+			continue
+		}
+		changes = append(changes, diff(dir, p)...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Dir != changes[j].Dir {
+			return changes[i].Dir < changes[j].Dir
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// parse returns a nil file for empty sides and false when src does not parse
+func parse(name string, src []byte) (*ast.File, bool) {
+	if src == nil {
+		return nil, true
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), name, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// isInternal reports whether the file belongs to a package that other modules
+// cannot import
+func isInternal(file string) bool {
+	for _, part := range strings.Split(path.Dir(file), "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+func diff(dir string, p *pkg) []Change {
+	var changes []Change
+	add := func(name string, d decl, op Op, from, to string, breaking bool) {
+		changes = append(changes, Change{
+			Dir:      dir,
+			Package:  p.name,
+			Name:     name,
+			Kind:     d.kind,
+			Op:       op,
+			Old:      from,
+			New:      to,
+			Breaking: breaking,
+		})
+	}
+
+	// Members are only reported when their type is unchanged, since a changed,
+	// added or removed type already describes them. A type declared in none of
+	// the touched files is unchanged too, as when methods live in another file.
+	stable := func(d decl) bool {
+		if d.parent == "" {
+			return true
+		}
+		o, ok := p.old[d.parent]
+		n, ok2 := p.new[d.parent]
+		return ok == ok2 && o.sig == n.sig
+	}
+
+	for name, o := range p.old {
+		if !stable(o) {
+			continue
+		}
+		n, ok := p.new[name]
+		switch {
+		case !ok:
+			add(name, o, Removed, o.sig, "", true)
+		case n.sig != o.sig || n.kind != o.kind:
+			add(name, n, Changed, o.sig, n.sig, true)
+		}
+	}
+	for name, n := range p.new {
+		if _, ok := p.old[name]; ok || !stable(n) {
+			continue
+		}
+		add(name, n, Added, "", n.sig, n.required)
+	}
+	return changes
+}
+
+func collect(f *ast.File, decls map[string]decl) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			collectFunc(d, decls)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					collectType(spec, decls)
+				case *ast.ValueSpec:
+					collectValue(d.Tok, spec, decls)
+				}
+			}
+		}
+	}
+}
+
+func collectFunc(d *ast.FuncDecl, decls map[string]decl) {
+	if !d.Name.IsExported() {
+		return
+	}
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		decls[d.Name.Name] = decl{kind: "func", sig: "func" + typeParams(d.Type.TypeParams) + signature(d.Type)}
+		return
+	}
+
+	recv := d.Recv.List[0].Type
+	base := recv
+	if star, ok := base.(*ast.StarExpr); ok {
+		base = star.X
+	}
+	switch x := base.(type) {
+	case *ast.IndexExpr:
+		base = x.X
+	case *ast.IndexListExpr:
+		base = x.X
+	}
+	typ, ok := base.(*ast.Ident)
+	if !ok || !typ.IsExported() {
+		return
+	}
+
+	decls[typ.Name+"."+d.Name.Name] = decl{
+		kind:   "method",
+		sig:    fmt.Sprintf("func (%s) %s%s", types.ExprString(recv), d.Name.Name, signature(d.Type)),
+		parent: typ.Name,
+	}
+}
+
+func collectType(spec *ast.TypeSpec, decls map[string]decl) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	name := spec.Name.Name
+	prefix := "type " + name + typeParams(spec.TypeParams) + " "
+	if spec.Assign.IsValid() {
+		prefix += "= "
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		decls[name] = decl{kind: "type", sig: prefix + "struct"}
+		for _, field := range t.Fields.List {
+			for _, fieldName := range fieldNames(field) {
+				if token.IsExported(fieldName) {
+					decls[name+"."+fieldName] = decl{kind: "field", sig: types.ExprString(field.Type), parent: name}
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		decls[name] = decl{kind: "type", sig: prefix + "interface"}
+		for _, method := range t.Methods.List {
+			switch ft := method.Type.(type) {
+			case *ast.FuncType:
+				for _, methodName := range method.Names {
+					if methodName.IsExported() {
+						decls[name+"."+methodName.Name] = decl{kind: "method", sig: methodName.Name + signature(ft), parent: name, required: true}
+					}
+				}
+			default:
+				// Embedded interfaces and type constraints
+				decls[name+"."+types.ExprString(ft)] = decl{kind: "embedded", sig: types.ExprString(ft), parent: name, required: true}
+			}
+		}
+	default:
+		decls[name] = decl{kind: "type", sig: prefix + types.ExprString(spec.Type)}
+	}
+}
+
+func collectValue(tok token.Token, spec *ast.ValueSpec, decls map[string]decl) {
+	sig := tok.String()
+	if spec.Type != nil {
+		sig += " " + types.ExprString(spec.Type)
+	}
+	for _, name := range spec.Names {
+		if name.IsExported() {
+			decls[name.Name] = decl{kind: tok.String(), sig: sig}
+		}
+	}
+}
+
+// fieldNames returns the names of a struct field, or the type name for
+// embedded fields
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+
+	t := field.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch x := t.(type) {
+	case *ast.IndexExpr:
+		t = x.X
+	case *ast.IndexListExpr:
+		t = x.X
+	}
+	switch x := t.(type) {
+	case *ast.Ident:
+		return []string{x.Name}
+	case *ast.SelectorExpr:
+		return []string{x.Sel.Name}
+	}
+	return nil
+}
+
+// signature renders the parameters and results of a function without their
+// names, since renaming a parameter does not change the API
+func signature(ft *ast.FuncType) string {
+	s := "(" + fieldTypes(ft.Params) + ")"
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return s
+	}
+
+	results := fieldTypes(ft.Results)
+	if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+		return s + " " + results
+	}
+	return s + " (" + results + ")"
+}
+
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range fields.List {
+		t := types.ExprString(field.Type)
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func typeParams(fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range fields.List {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+types.ExprString(field.Type))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
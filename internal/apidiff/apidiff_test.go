@@ -0,0 +1,142 @@
+package apidiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const oldClient = `package client
+
+type Client struct {
+	BaseURL string
+	Timeout int
+	secret  string
+}
+
+func (c *Client) Do(req *Request) error { return nil }
+
+func (c *Client) helper() {}
+
+type Request struct{ Path string }
+
+type Doer interface {
+	Do(req *Request) error
+}
+
+func New(baseURL string) *Client { return nil }
+
+func Parse(s string) (int, error) { return 0, nil }
+
+const Version = "1.0"
+
+type Mode int
+`
+
+const newClient = `package client
+
+import "context"
+
+type Client struct {
+	BaseURL string
+	Retries int
+	token   string
+}
+
+func (c *Client) Do(ctx context.Context, req *Request) error { return nil }
+
+type Request struct{ Path string }
+
+type Doer interface {
+	Do(req *Request) error
+	Close() error
+}
+
+func New(url string) *Client { return nil }
+
+func Format(n int) string { return "" }
+
+const Version = "2.0"
+
+type Mode string
+`
+
+func TestCompare(t *testing.T) {
+	changes := Compare([]File{{Path: "client/client.go", Old: []byte(oldClient), New: []byte(newClient)}})
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	require.Equal(t, []string{
+		"changed method client.Client.Do from func (*Client) Do(*Request) error to func (*Client) Do(context.Context, *Request) error",
+		"added field client.Client.Retries (int)",
+		"removed field client.Client.Timeout (int)",
+		"added method client.Doer.Close (Close() error)",
+		"added func client.Format (func(int) string)",
+		"changed type client.Mode from type Mode int to type Mode string",
+		"removed func client.Parse (func(string) (int, error))",
+	}, stripBreaking(changes), "Renamed parameters, unexported members and constant values are ignored")
+
+	require.Equal(t, "BREAKING: removed func client.Parse (func(string) (int, error))", got[6])
+	require.True(t, changes[3].Breaking, "Interface methods must be added by implementations")
+	require.False(t, changes[1].Breaking)
+	require.False(t, changes[4].Breaking)
+	require.True(t, HasBreaking(changes))
+}
+
+func stripBreaking(changes []Change) []string {
+	var s []string
+	for _, c := range changes {
+		c.Breaking = false
+		s = append(s, c.String())
+	}
+	return s
+}
+
+func TestCompareMovedBetweenFiles(t *testing.T) {
+	changes := Compare([]File{
+		{Path: "pkg/a.go", Old: []byte("package pkg\n\nfunc Run() {}\n"), New: []byte("package pkg\n")},
+		{Path: "pkg/b.go", New: []byte("package pkg\n\nfunc Run() {}\n")},
+	})
+	require.Empty(t, changes)
+}
+
+func TestCompareMethodsApartFromTheirType(t *testing.T) {
+	oldMethods := "package client\n\nfunc (c *Client) Do(req string) error { return nil }\n\nfunc (c *Client) Close() error { return nil }\n"
+	newMethods := "package client\n\nfunc (c *Client) Do(req int) error { return nil }\n\nfunc (c *Client) Reset() {}\n"
+
+	changes := Compare([]File{{Path: "client/methods.go", Old: []byte(oldMethods), New: []byte(newMethods)}})
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	require.Equal(t, []string{
+		"BREAKING: removed method client.Client.Close (func (*Client) Close() error)",
+		"BREAKING: changed method client.Client.Do from func (*Client) Do(string) error to func (*Client) Do(int) error",
+		"added method client.Client.Reset (func (*Client) Reset())",
+	}, got, "Methods are compared even when their type is declared in an untouched file")
+	require.True(t, HasBreaking(changes))
+}
+
+func TestCompareAddedAndRemovedTypes(t *testing.T) {
+	changes := Compare([]File{
+		{Path: "old.go", Old: []byte("package pkg\n\ntype Gone struct{ A int }\n\nfunc (Gone) M() {}\n")},
+		{Path: "new.go", New: []byte("package pkg\n\ntype Fresh[T any] struct{ B T }\n")},
+	})
+	require.Len(t, changes, 2, "Members of added and removed types are not listed")
+	require.Equal(t, "added type pkg.Fresh (type Fresh[T any] struct)", changes[0].String())
+	require.Equal(t, "BREAKING: removed type pkg.Gone (type Gone struct)", changes[1].String())
+}
+
+func TestCompareSkipsFiles(t *testing.T) {
+	removed := []byte("package pkg\n\nfunc Run() {}\n")
+	changes := Compare([]File{
+		{Path: "pkg/run_test.go", Old: removed},
+		{Path: "internal/pkg/run.go", Old: removed},
+		{Path: "cmd/tool/main.go", Old: []byte("package main\n\nfunc Run() {}\n")},
+		{Path: "pkg/broken.go", Old: removed, New: []byte("package pkg\n\nfunc {")},
+		{Path: "README.md", Old: removed},
+	})
+	require.Empty(t, changes)
+}
@@ -0,0 +1,226 @@
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amosehiguese/zeus-ai/internal/conventional"
+)
+
+const (
+	FormatMarkdown       = "markdown"
+	FormatKeepAChangelog = "keepachangelog"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatMarkdown, FormatKeepAChangelog}
+
+// Entry is a commit in a changelog
+type Entry struct {
+	Hash string
+	conventional.Commit
+	// Conventional is false for messages that don't follow Conventional
+	// Commits; their title is kept as the description
+	Conventional bool
+}
+
+// NewEntry parses the message of commit hash
+func NewEntry(hash, message string) Entry {
+	c, ok := conventional.Parse(message)
+	return Entry{Hash: hash, Commit: c, Conventional: ok}
+}
+
+// Changelog is the list of changes in a release
+type Changelog struct {
+	// Title is the version or range the changes belong to
+	Title string
+	Date  time.Time
+	// Highlights are optional summary bullets shown before the changes
+	Highlights []string
+	Entries    []Entry
+	// All includes types that are not user-facing, such as docs, tests and
+	// chores, and commits that are not conventional
+	All bool
+}
+
+// sectionTitles are the markdown headings of each type, with "other" for
+// commits that are not conventional
+var sectionTitles = map[string]string{
+	"feat":     "Features",
+	"fix":      "Bug Fixes",
+	"perf":     "Performance Improvements",
+	"refactor": "Code Refactoring",
+	"revert":   "Reverts",
+	"docs":     "Documentation",
+	"style":    "Styles",
+	"test":     "Tests",
+	"build":    "Build System",
+	"ci":       "Continuous Integration",
+	"chore":    "Chores",
+	"other":    "Other Changes",
+}
+
+// userFacing are the types listed without All
+var userFacing = map[string]bool{"feat": true, "fix": true, "perf": true, "revert": true}
+
+// Listed reports whether e appears in the changelog in any format
+func (c Changelog) Listed(e Entry) bool {
+	if c.All || e.Breaking {
+		return true
+	}
+	return e.Conventional && (userFacing[e.Type] || e.Type == "refactor")
+}
+
+// Render formats the changelog in the given format
+func (c Changelog) Render(format string) (string, error) {
+	switch format {
+	case FormatMarkdown, "":
+		return c.markdown(), nil
+	case FormatKeepAChangelog:
+		return c.keepAChangelog(), nil
+	default:
+		return "", fmt.Errorf("unknown changelog format %q: must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func (c Changelog) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s (%s)\n", c.Title, c.Date.Format("2006-01-02"))
+	c.writeHighlights(&b)
+
+	var breaking []Entry
+	for _, e := range c.Entries {
+		if e.Breaking {
+			breaking = append(breaking, e)
+		}
+	}
+	if len(breaking) > 0 {
+		b.WriteString("\n### ⚠ BREAKING CHANGES\n\n")
+		for _, e := range sortByScope(breaking) {
+			writeEntry(&b, e, breakingText(e))
+		}
+	}
+
+	sections := map[string][]Entry{}
+	for _, e := range c.Entries {
+		key := e.Type
+		if _, known := sectionTitles[key]; !e.Conventional || !known {
+			key = "other"
+		}
+		if c.All || userFacing[key] {
+			sections[key] = append(sections[key], e)
+		}
+	}
+
+	for _, key := range append(append([]string(nil), conventional.Types...), "other") {
+		if len(sections[key]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", sectionTitles[key])
+		for _, e := range sortByScope(sections[key]) {
+			writeEntry(&b, e, e.Description)
+		}
+	}
+
+	return b.String()
+}
+
+// keepAChangelog renders the sections of https://keepachangelog.com, with
+// breaking changes marked in the section of their type
+func (c Changelog) keepAChangelog() string {
+	var b strings.Builder
+	if strings.EqualFold(c.Title, "unreleased") {
+		b.WriteString("## [Unreleased]\n")
+	} else {
+		fmt.Fprintf(&b, "## [%s] - %s\n", strings.TrimPrefix(c.Title, "v"), c.Date.Format("2006-01-02"))
+	}
+	c.writeHighlights(&b)
+
+	order := []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+	sections := map[string][]Entry{}
+	for _, e := range c.Entries {
+		section := keepAChangelogSection(e, c.All)
+		if section != "" {
+			sections[section] = append(sections[section], e)
+		}
+	}
+
+	for _, section := range order {
+		if len(sections[section]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section)
+		for _, e := range sortByScope(sections[section]) {
+			text := e.Description
+			if e.Breaking {
+				text = "**BREAKING:** " + breakingText(e)
+			}
+			writeEntry(&b, e, text)
+		}
+	}
+
+	return b.String()
+}
+
+func keepAChangelogSection(e Entry, all bool) string {
+	switch {
+	case !e.Conventional:
+	case e.Type == "feat":
+		return "Added"
+	case e.Type == "fix" && e.Scope == "security":
+		return "Security"
+	case e.Type == "fix":
+		return "Fixed"
+	case e.Type == "revert":
+		return "Removed"
+	case e.Type == "perf" || e.Type == "refactor" || e.Breaking:
+		return "Changed"
+	}
+
+	if all {
+		return "Changed"
+	}
+	return ""
+}
+
+func (c Changelog) writeHighlights(b *strings.Builder) {
+	if len(c.Highlights) == 0 {
+		return
+	}
+
+	b.WriteString("\n### Highlights\n\n")
+	for _, h := range c.Highlights {
+		fmt.Fprintf(b, "- %s\n", h)
+	}
+}
+
+// breakingText is the description of a breaking change followed by its note
+func breakingText(e Entry) string {
+	if e.BreakingNote == "" {
+		return e.Description
+	}
+	return e.Description + "\n  " + strings.ReplaceAll(e.BreakingNote, "\n", "\n  ")
+}
+
+func writeEntry(b *strings.Builder, e Entry, text string) {
+	b.WriteString("- ")
+	if e.Scope != "" {
+		fmt.Fprintf(b, "**%s:** ", e.Scope)
+	}
+	title, rest, _ := strings.Cut(text, "\n")
+	fmt.Fprintf(b, "%s (%.7s)", title, e.Hash)
+	if rest != "" {
+		b.WriteString("\n" + rest)
+	}
+	b.WriteString("\n")
+}
+
+// sortByScope groups entries by scope, listing unscoped entries first and
+// keeping the commit order within a scope
+func sortByScope(entries []Entry) []Entry {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Scope < sorted[j].Scope })
+	return sorted
+}
@@ -0,0 +1,99 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testChangelog() Changelog {
+	return Changelog{
+		Title: "v1.3.0",
+		Date:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Entries: []Entry{
+			NewEntry("aaaaaaa111", "feat(ui): add dark mode"),
+			NewEntry("bbbbbbb222", "fix: handle empty diff"),
+			NewEntry("ccccccc333", "feat(api)!: drop v1 endpoints\n\nBREAKING CHANGE: use /v2"),
+			NewEntry("ddddddd444", "docs: update readme"),
+			NewEntry("eeeeeee555", "Bump deps"),
+			NewEntry("fffffff666", "fix(security): escape HTML"),
+		},
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	out, err := testChangelog().Render(FormatMarkdown)
+	require.NoError(t, err)
+	require.Equal(t, `## v1.3.0 (2024-05-01)
+
+### ⚠ BREAKING CHANGES
+
+- **api:** drop v1 endpoints (ccccccc)
+  use /v2
+
+### Features
+
+- **api:** drop v1 endpoints (ccccccc)
+- **ui:** add dark mode (aaaaaaa)
+
+### Bug Fixes
+
+- handle empty diff (bbbbbbb)
+- **security:** escape HTML (fffffff)
+`, out)
+}
+
+func TestRenderMarkdownAll(t *testing.T) {
+	c := testChangelog()
+	c.All = true
+	c.Highlights = []string{"Dark mode"}
+	out, err := c.Render(FormatMarkdown)
+	require.NoError(t, err)
+	require.Contains(t, out, "### Highlights\n\n- Dark mode\n")
+	require.Contains(t, out, "### Documentation\n\n- update readme (ddddddd)\n")
+	require.Contains(t, out, "### Other Changes\n\n- Bump deps (eeeeeee)\n")
+}
+
+func TestRenderKeepAChangelog(t *testing.T) {
+	out, err := testChangelog().Render(FormatKeepAChangelog)
+	require.NoError(t, err)
+	require.Equal(t, `## [1.3.0] - 2024-05-01
+
+### Added
+
+- **api:** **BREAKING:** drop v1 endpoints (ccccccc)
+  use /v2
+- **ui:** add dark mode (aaaaaaa)
+
+### Fixed
+
+- handle empty diff (bbbbbbb)
+
+### Security
+
+- **security:** escape HTML (fffffff)
+`, out)
+
+	c := testChangelog()
+	c.Title = "Unreleased"
+	out, err = c.Render(FormatKeepAChangelog)
+	require.NoError(t, err)
+	require.Contains(t, out, "## [Unreleased]\n")
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	_, err := testChangelog().Render("html")
+	require.Error(t, err)
+}
+
+func TestListed(t *testing.T) {
+	c := testChangelog()
+	var listed []string
+	for _, e := range c.Entries {
+		if c.Listed(e) {
+			listed = append(listed, e.Hash[:1])
+		}
+	}
+	require.Equal(t, []string{"a", "b", "c", "f"}, listed)
+}
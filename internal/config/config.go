@@ -4,87 +4,491 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"github.com/amosehiguese/zeus-ai/internal/scope"
 )
 
+// Config holds the effective zeus-ai settings. Values are resolved with the
+// following precedence, highest first: command-line flags, ZEUS_* environment
+// variables, the active profile, the repository's .zeusrc, ~/.zeusrc and
+// built-in defaults.
 type Config struct {
 	Provider     string
 	APIKey       string
+	APIKeyCmd    string
+	APIKeyFile   string
 	Model        string
 	DefaultStyle string
+	BaseURL      string
+	IncludeBody  bool
+	AlwaysEdit   bool
+	Editor       string
+	Signoff      bool
+	// GPGSign is empty to follow git's commit.gpgsign, "true" or "false",
+	// or the key to sign with
+	GPGSign   string
+	AutoStage bool
+	DryRun    bool
+	Audit     bool
+	// UsageLedger records token usage and cost for zeusctl usage
+	UsageLedger bool
+	Roster      string
+	// BranchPattern is the pattern of names suggested by zeusctl branch
+	BranchPattern string
+	// ReviewFailOn is the lowest severity of review findings that fails
+	// zeusctl review, or "none"
+	ReviewFailOn string
+	// ReviewFailOnError makes zeusctl review fail when the review cannot be
+	// run with --fail-on set, instead of letting the commit through
+	ReviewFailOnError bool
+	Policy            Policy
+	// Trailers are added to every commit message, as "Key: value"
+	Trailers []string
+	Tickets  Tickets
+	Scopes   Scopes
+	// Profile is the name of the active profile, if any
+	Profile string
+
+	// origins maps each setting key to where its effective value came from
+	origins map[string]string
+}
+
+// Policy restricts which providers a repository may use. It is only read from
+// config files, and a repository's .zeusrc takes precedence over ~/.zeusrc so
+// that user-level settings cannot relax a repository's policy.
+type Policy struct {
+	AllowedProviders []string
+	Network          string
+	// Source is the file the effective policy was read from
+	Source string
+}
+
+const (
+	NetworkAny       = "any"
+	NetworkLocalOnly = "local-only"
+)
+
+// Tickets describes how ticket IDs are extracted from branch names and where
+// they go in commit messages. Each key set in the repository's .zeusrc
+// replaces the one from ~/.zeusrc.
+type Tickets struct {
+	// Patterns are regular expressions matched against the branch name. The
+	// ID is the "ticket" group, the first group or else the whole match.
+	Patterns []string
+	// Placement is where the ID goes in the message, one of the Placement* values
+	Placement string
+	// Trailer is the trailer key used with PlacementTrailer
+	Trailer string
+	// RequiredBranches are globs of branches that must reference a ticket
+	RequiredBranches []string
+}
+
+// Scopes describes how conventional commit scopes are inferred from the
+// changed paths. Each key set in the repository's .zeusrc replaces the one
+// from ~/.zeusrc.
+type Scopes struct {
+	// Rules are "glob=scope" entries, the first matching one wins
+	Rules []string
+	// Detect infers the scope of paths no rule matches from the nearest
+	// go.mod, package.json or Cargo.toml below the repository root
+	Detect bool
+}
+
+const (
+	PlacementPrefix  = "prefix"
+	PlacementScope   = "scope"
+	PlacementTrailer = "trailer"
+)
+
+func defaults() *Config {
+	return &Config{
+		Provider:      "ollama",  // Default provider
+		Model:         "mistral", // Default model
+		DefaultStyle:  "conventional",
+		BranchPattern: "{type}/{slug}",
+		ReviewFailOn:  "none",
+		UsageLedger:   true,
+		origins:       map[string]string{},
+	}
+}
+
+// Origin describes where the effective value of key came from: "default",
+// "file:<path>", "profile:<name>", "env:<VAR>" or "flag:--<name>".
+func (c *Config) Origin(key string) string {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+	return "default"
+}
+
+func (c *Config) set(s Setting, value, origin string) error {
+	if err := s.Set(c, value); err != nil {
+		return err
+	}
+	if c.origins == nil {
+		c.origins = map[string]string{}
+	}
+	c.origins[s.Key] = origin
+	return nil
 }
 
 func Load() (*Config, error) {
-	config := &Config{
-		Provider:     "ollama",  // Default provider
-		Model:        "mistral", // Default model
-		DefaultStyle: "conventional",
+	config := defaults()
+
+	homeFile, repoFile := configFiles()
+	if err := validateFiles(homeFile, repoFile); err != nil {
+		return nil, err
 	}
 
-	viper.SetConfigName(".zeusrc")
-	viper.SetConfigType("yaml")
+	// Config files, user-level first so the repository file wins. Each file
+	// is parsed once and every section below reads from that parse.
+	files, err := readFiles(homeFile, repoFile)
+	if err != nil {
+		return nil, err
+	}
 
-	// Look for config in home directory
-	home, err := os.UserHomeDir()
-	if err == nil {
-		viper.AddConfigPath(home)
-	}
-
-	// Look for config in current directory and all parent directories
-	currentDir, err := os.Getwd()
-	if err == nil {
-		for {
-			viper.AddConfigPath(currentDir)
-			parent := filepath.Dir(currentDir)
-			if parent == currentDir {
-				break
+	for _, file := range files {
+		for _, s := range Settings {
+			if !file.v.IsSet(s.Key) {
+				continue
+			}
+			if s.UserOnly && file.repo {
+				return nil, userOnlyError(file.path, s.Key)
+			}
+			if err = config.set(s, file.v.GetString(s.Key), "file:"+file.path); err != nil {
+				return nil, fmt.Errorf("%s: %w", file.path, err)
 			}
-			currentDir = parent
 		}
 	}
 
-	// Environment variables
-	viper.SetEnvPrefix("ZEUS")
-	viper.AutomaticEnv()
+	// The active profile overrides the plain config file values
+	profiles, err := loadProfiles(files)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := activeProfile(profiles)
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		config.Profile = profile.Name
+		for _, s := range Settings {
+			value, ok := profile.Values[s.Key]
+			if !ok {
+				continue
+			}
+			if err = config.set(s, value, "profile:"+profile.Name); err != nil {
+				return nil, fmt.Errorf("%s: profile %s: %w", profile.Source, profile.Name, err)
+			}
+		}
+	}
 
-	// Read the config file
-	if err := viper.ReadInConfig(); err != nil {
-		// It's okay if there is no config file
-		var notFoundErr viper.ConfigFileNotFoundError
-		if !errors.As(err, &notFoundErr) {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+	// Environment variables override config files and profiles
+	for _, s := range Settings {
+		value, ok := os.LookupEnv(s.Env())
+		if !ok || value == "" {
+			continue
+		}
+		if err := config.set(s, value, "env:"+s.Env()); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Env(), err)
 		}
 	}
 
-	// Override with environment variables or config file values
-	if viper.IsSet("provider") {
-		config.Provider = viper.GetString("provider")
+	policy, err := loadPolicy(files)
+	if err != nil {
+		return nil, err
 	}
-	if viper.IsSet("api_key") {
-		config.APIKey = viper.GetString("api_key")
+	config.Policy = policy
+
+	config.Trailers = loadTrailers(files)
+	if config.Tickets, err = loadTickets(files); err != nil {
+		return nil, err
 	}
-	if viper.IsSet("model") {
-		config.Model = viper.GetString("model")
+	if config.Scopes, err = loadScopes(files); err != nil {
+		return nil, err
 	}
-	if viper.IsSet("default_style") {
-		config.DefaultStyle = viper.GetString("default_style")
+
+	return config, nil
+}
+
+// LoadEditor returns the configured editor without validating the config
+// files, so that a file Load rejects can still be opened to fix it. Files that
+// cannot be parsed are skipped, and an empty result leaves the choice to
+// $EDITOR or $VISUAL.
+func LoadEditor() string {
+	if editor := os.Getenv("ZEUS_EDITOR"); editor != "" {
+		return editor
 	}
 
-	// Check for environment variables
-	if os.Getenv("ZEUS_PROVIDER") != "" {
-		config.Provider = os.Getenv("ZEUS_PROVIDER")
+	homeFile, repoFile := configFiles()
+	for _, file := range []string{repoFile, homeFile} {
+		if file == "" {
+			continue
+		}
+		v, err := readFile(file)
+		if err != nil {
+			continue
+		}
+		if editor, ok := v.Get("editor").(string); ok && editor != "" {
+			return editor
+		}
 	}
-	if os.Getenv("ZEUS_API_KEY") != "" {
-		config.APIKey = os.Getenv("ZEUS_API_KEY")
+	return ""
+}
+
+// validateFiles checks every existing config file against the schema and
+// reports the issues of all of them together
+func validateFiles(files ...string) error {
+	var all ValidationError
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		err := ValidateFile(file)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			all.Issues = append(all.Issues, validationErr.Issues...)
+			continue
+		}
+		if err != nil {
+			return err
+		}
 	}
-	if os.Getenv("ZEUS_MODEL") != "" {
-		config.Model = os.Getenv("ZEUS_MODEL")
+
+	if len(all.Issues) > 0 {
+		return &all
 	}
-	if os.Getenv("ZEUS_DEFAULT_STYLE") != "" {
-		config.DefaultStyle = os.Getenv("ZEUS_DEFAULT_STYLE")
+	return nil
+}
+
+// ApplyFlags overrides settings with the flags in fs that were set explicitly on
+// the command line. Flags left at their default do not replace configured values.
+func (c *Config) ApplyFlags(fs *pflag.FlagSet) error {
+	for _, s := range Settings {
+		if s.Flag == "" {
+			continue
+		}
+		flag := fs.Lookup(s.Flag)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if err := c.set(s, flag.Value.String(), "flag:--"+s.Flag); err != nil {
+			return fmt.Errorf("--%s: %w", s.Flag, err)
+		}
 	}
 
-	return config, nil
+	return nil
+}
+
+// loadPolicy reads the policy from ~/.zeusrc and then from the repository's
+// .zeusrc, letting each key set in the repository file replace the user one.
+func loadPolicy(files []configFile) (Policy, error) {
+	var policy Policy
+
+	for _, file := range files {
+		if file.v.IsSet("policy.allowed_providers") {
+			policy.AllowedProviders = file.v.GetStringSlice("policy.allowed_providers")
+			policy.Source = file.path
+		}
+		if file.v.IsSet("policy.network") {
+			policy.Network = file.v.GetString("policy.network")
+			policy.Source = file.path
+		}
+	}
+
+	switch policy.Network {
+	case "", NetworkAny, NetworkLocalOnly:
+	default:
+		return policy, fmt.Errorf("invalid policy.network %q in %s: must be %q or %q", policy.Network, policy.Source, NetworkAny, NetworkLocalOnly)
+	}
+
+	return policy, nil
+}
+
+// loadTrailers reads the default trailers from ~/.zeusrc followed by those of
+// the repository's .zeusrc
+func loadTrailers(files []configFile) []string {
+	var trailers []string
+	for _, file := range files {
+		trailers = append(trailers, stringList(file.v, "trailers")...)
+	}
+	return trailers
+}
+
+// loadTickets reads the tickets section from ~/.zeusrc and then from the
+// repository's .zeusrc, letting each key set in the repository file replace
+// the user one
+func loadTickets(files []configFile) (Tickets, error) {
+	tickets := Tickets{Placement: PlacementTrailer, Trailer: "Refs"}
+
+	for _, file := range files {
+		v := file.v
+		if v.IsSet("tickets.patterns") {
+			tickets.Patterns = stringList(v, "tickets.patterns")
+		}
+		if v.IsSet("tickets.placement") {
+			tickets.Placement = v.GetString("tickets.placement")
+		}
+		if v.IsSet("tickets.trailer") {
+			tickets.Trailer = v.GetString("tickets.trailer")
+		}
+		if v.IsSet("tickets.required_branches") {
+			tickets.RequiredBranches = stringList(v, "tickets.required_branches")
+		}
+	}
+
+	for _, pattern := range tickets.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return tickets, fmt.Errorf("invalid tickets.patterns entry %q: %w", pattern, err)
+		}
+	}
+	for _, glob := range tickets.RequiredBranches {
+		if _, err := path.Match(glob, ""); err != nil {
+			return tickets, fmt.Errorf("invalid tickets.required_branches entry %q: %w", glob, err)
+		}
+	}
+
+	return tickets, nil
+}
+
+// loadScopes reads the scopes section from ~/.zeusrc and then from the
+// repository's .zeusrc, letting each key set in the repository file replace
+// the user one
+func loadScopes(files []configFile) (Scopes, error) {
+	scopes := Scopes{Detect: true}
+
+	for _, file := range files {
+		v := file.v
+		if v.IsSet("scopes.rules") {
+			scopes.Rules = stringList(v, "scopes.rules")
+		}
+		if v.IsSet("scopes.detect") {
+			scopes.Detect = v.GetBool("scopes.detect")
+		}
+	}
+
+	if _, err := scope.ParseRules(scopes.Rules); err != nil {
+		return scopes, fmt.Errorf("invalid scopes.rules: %w", err)
+	}
+
+	return scopes, nil
+}
+
+// stringList returns a list value, accepting a single string in its place
+// without splitting it on whitespace as viper would
+func stringList(v *viper.Viper, key string) []string {
+	if s, ok := v.Get(key).(string); ok {
+		return []string{s}
+	}
+	return v.GetStringSlice(key)
+}
+
+// configFile is a config file parsed by viper
+type configFile struct {
+	path string
+	v    *viper.Viper
+	// repo is set for the repository's .zeusrc, which may not set UserOnly
+	// settings
+	repo bool
+}
+
+// readFiles parses the config files that exist, user-level first, so that
+// each section is read from the same parse
+func readFiles(homeFile, repoFile string) ([]configFile, error) {
+	var files []configFile
+	for _, path := range []string{homeFile, repoFile} {
+		if path == "" {
+			continue
+		}
+		v, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, configFile{path: path, v: v, repo: path == repoFile})
+	}
+	return files, nil
+}
+
+// userOnlyError reports a UserOnly setting found in the repository's .zeusrc
+func userOnlyError(file, key string) error {
+	return fmt.Errorf("%s: %s can only be set in ~/.zeusrc, the environment or on the command line, as a repository could use it to run commands or leak secrets", file, key)
+}
+
+func readFile(file string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+	}
+
+	return v, nil
+}
+
+// configFiles returns the user-level config file and the nearest repository
+// config file found by walking up from the current directory. Either may be
+// empty when the file does not exist.
+func configFiles() (homeFile, repoFile string) {
+	if home, err := os.UserHomeDir(); err == nil {
+		if path := filepath.Join(home, ".zeusrc"); fileExists(path) {
+			homeFile = path
+		}
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return homeFile, ""
+	}
+	for {
+		if path := filepath.Join(dir, ".zeusrc"); path != homeFile && fileExists(path) {
+			return homeFile, path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return homeFile, ""
+		}
+		dir = parent
+	}
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// StateDir returns the directory where zeus-ai keeps state such as the audit log.
+// It honours $XDG_STATE_HOME and falls back to ~/.local/state/zeus-ai.
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "zeus-ai"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", "zeus-ai"), nil
+}
+
+// CacheDir returns the directory where zeus-ai caches provider responses.
+// It honours $XDG_CACHE_HOME and falls back to ~/.cache/zeus-ai.
+func CacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "zeus-ai"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".cache", "zeus-ai"), nil
 }
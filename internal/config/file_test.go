@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAndUnsetFileValue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-config-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, ".zeusrc")
+	err = os.WriteFile(path, []byte("# team settings\nprovider: openrouter # cloud\n"), 0o644)
+	require.NoError(t, err, "Failed to write config file")
+
+	require.NoError(t, SetFileValue(path, "model", "new-model"))
+	require.NoError(t, SetFileValue(path, "auto_stage", "true"))
+	require.NoError(t, SetFileValue(path, "provider", "ollama"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "# team settings", "Expected comments to be preserved")
+	require.Contains(t, string(content), "provider: ollama")
+	require.Contains(t, string(content), "auto_stage: true")
+
+	value, ok, err := GetFileValue(path, "model")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "new-model", value)
+
+	removed, err := UnsetFileValue(path, "model")
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	_, ok, err = GetFileValue(path, "model")
+	require.NoError(t, err)
+	require.False(t, ok, "Expected model to be removed")
+}
+
+func TestLoadRecordsOrigins(t *testing.T) {
+	setupConfigDirs(t, "", "provider: openrouter\n")
+	t.Setenv("ZEUS_MODEL", "env-model")
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+
+	repoFile, err := filepath.Abs(".zeusrc")
+	require.NoError(t, err)
+	require.Equal(t, "file:"+repoFile, cfg.Origin("provider"))
+	require.Equal(t, "env:ZEUS_MODEL", cfg.Origin("model"))
+	require.Equal(t, "default", cfg.Origin("default_style"))
+}
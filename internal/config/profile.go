@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/git"
+)
+
+// Profile is a named set of settings that is layered over the config files
+// when selected with --profile, ZEUS_PROFILE or by matching the current branch
+// or repository path.
+type Profile struct {
+	Name string
+	// Values holds the settings the profile overrides, keyed by setting key
+	Values map[string]string
+	// Branches are glob patterns matched against the current branch name
+	Branches []string
+	// Paths are glob patterns matched against the repository root
+	Paths []string
+	// Source is the config file that defines the profile
+	Source string
+}
+
+// selectedProfile is set from the --profile flag and takes precedence over ZEUS_PROFILE
+var selectedProfile string
+
+// SelectProfile makes Load apply the named profile
+func SelectProfile(name string) {
+	selectedProfile = name
+}
+
+// loadProfiles reads the profiles defined in the config files. A profile in the
+// repository file replaces a profile of the same name in ~/.zeusrc.
+func loadProfiles(files []configFile) (map[string]*Profile, error) {
+	profiles := map[string]*Profile{}
+
+	for _, file := range files {
+		for name := range file.v.GetStringMap("profiles") {
+			sub := file.v.Sub("profiles." + name)
+			if sub == nil {
+				return nil, fmt.Errorf("%s: profile %q must be a mapping", file.path, name)
+			}
+
+			profile := &Profile{
+				Name:     name,
+				Values:   map[string]string{},
+				Branches: sub.GetStringSlice("branches"),
+				Paths:    sub.GetStringSlice("paths"),
+				Source:   file.path,
+			}
+			for _, s := range Settings {
+				if !sub.IsSet(s.Key) {
+					continue
+				}
+				if s.UserOnly && file.repo {
+					return nil, userOnlyError(file.path, "profiles."+name+"."+s.Key)
+				}
+				profile.Values[s.Key] = sub.GetString(s.Key)
+			}
+			profiles[name] = profile
+		}
+	}
+
+	return profiles, nil
+}
+
+// activeProfile returns the profile to apply, if any. An explicitly selected
+// profile wins over ZEUS_PROFILE, which wins over branch and path rules.
+func activeProfile(profiles map[string]*Profile) (*Profile, error) {
+	name := selectedProfile
+	if name == "" {
+		name = os.Getenv("ZEUS_PROFILE")
+	}
+	if name != "" {
+		profile, ok := profiles[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+		return profile, nil
+	}
+
+	// Check profiles in a stable order so the first match is deterministic
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	branch, _ := git.CurrentBranch()
+	root, _ := git.RepoRoot()
+	for _, name := range names {
+		if profiles[name].matches(branch, root) {
+			return profiles[name], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (p *Profile) matches(branch, root string) bool {
+	if branch != "" {
+		for _, pattern := range p.Branches {
+			if ok, _ := path.Match(pattern, branch); ok {
+				return true
+			}
+		}
+	}
+
+	if root != "" {
+		for _, pattern := range p.Paths {
+			if ok, _ := filepath.Match(expandHome(pattern), root); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func expandHome(pattern string) string {
+	if pattern != "~" && !strings.HasPrefix(pattern, "~/") {
+		return pattern
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return pattern
+	}
+	return filepath.Join(home, strings.TrimPrefix(pattern, "~"))
+}
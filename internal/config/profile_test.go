@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `
+provider: ollama
+model: mistral
+profiles:
+  fast:
+    model: qwen2.5-coder:1.5b
+    default_style: simple
+  release:
+    provider: openrouter
+    model: anthropic/claude-sonnet-4
+    include_body: true
+    branches: ["release/*"]
+`
+
+func TestProfileSelectedByName(t *testing.T) {
+	setupConfigDirs(t, "", profilesConfig)
+	t.Setenv("ZEUS_PROFILE", "fast")
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+	require.Equal(t, "fast", cfg.Profile)
+	require.Equal(t, "qwen2.5-coder:1.5b", cfg.Model, "Profile should override file value")
+	require.Equal(t, "simple", cfg.DefaultStyle)
+	require.Equal(t, "ollama", cfg.Provider, "Keys the profile does not set should keep file value")
+	require.Equal(t, "profile:fast", cfg.Origin("model"))
+
+	// The flag selection wins over ZEUS_PROFILE
+	SelectProfile("release")
+	defer SelectProfile("")
+	cfg, err = Load()
+	require.NoError(t, err, "Failed to load config")
+	require.Equal(t, "release", cfg.Profile)
+
+	SelectProfile("missing")
+	_, err = Load()
+	require.ErrorContains(t, err, "unknown profile")
+}
+
+func TestProfileSelectedByBranch(t *testing.T) {
+	setupConfigDirs(t, "", profilesConfig)
+	os.Unsetenv("ZEUS_PROFILE")
+
+	require.NoError(t, exec.Command("git", "init", "-q").Run())
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", "main").Run())
+
+	cfg, err := Load()
+	require.NoError(t, err, "Failed to load config")
+	require.Empty(t, cfg.Profile, "No profile should match main")
+	require.Equal(t, "mistral", cfg.Model)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", "release/1.4").Run())
+
+	cfg, err = Load()
+	require.NoError(t, err, "Failed to load config")
+	require.Equal(t, "release", cfg.Profile)
+	require.Equal(t, "openrouter", cfg.Provider)
+	require.True(t, cfg.IncludeBody)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Roster maps team handles to co-author identities in the "Name <email>"
+// form used by Co-authored-by trailers. It is read from a YAML file such as:
+//
+//	alice: Alice Doe <alice@example.com>
+//	bob: Bob Roe <bob@example.com>
+type Roster map[string]string
+
+// LoadRoster reads a roster file
+func LoadRoster(path string) (Roster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read roster: %w", err)
+	}
+
+	var roster Roster
+	if err = yaml.Unmarshal(data, &roster); err != nil {
+		return nil, fmt.Errorf("failed to parse roster %s: %w", path, err)
+	}
+	for handle, identity := range roster {
+		if !strings.Contains(identity, "<") || !strings.HasSuffix(identity, ">") {
+			return nil, fmt.Errorf("invalid roster entry %q in %s: expected \"Name <email>\"", handle, path)
+		}
+	}
+	return roster, nil
+}
+
+// Lookup finds the identity for query, which is either a handle or a part of
+// a name or email that matches a single entry. Identities already in the
+// "Name <email>" form are returned unchanged.
+func (r Roster) Lookup(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if strings.Contains(query, "<") && strings.HasSuffix(query, ">") {
+		return query, nil
+	}
+
+	for handle, identity := range r {
+		if strings.EqualFold(handle, query) {
+			return identity, nil
+		}
+	}
+
+	var matches []string
+	lower := strings.ToLower(query)
+	for _, identity := range r {
+		if strings.Contains(strings.ToLower(identity), lower) {
+			matches = append(matches, identity)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no co-author matches %q in the roster", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches several co-authors: %s", query, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRosterLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+alice: Alice Doe <alice@example.com>
+bob: Bob Roe <bob@example.com>
+bobby: Bobby Tables <bobby@example.org>
+`), 0o644))
+
+	roster, err := LoadRoster(path)
+	require.NoError(t, err)
+
+	identity, err := roster.Lookup("Alice")
+	require.NoError(t, err)
+	require.Equal(t, "Alice Doe <alice@example.com>", identity, "Handles match regardless of case")
+
+	identity, err = roster.Lookup("example.org")
+	require.NoError(t, err)
+	require.Equal(t, "Bobby Tables <bobby@example.org>", identity, "A unique part of a name or email matches")
+
+	identity, err = roster.Lookup("bob")
+	require.NoError(t, err)
+	require.Equal(t, "Bob Roe <bob@example.com>", identity, "An exact handle wins over partial matches")
+
+	_, err = roster.Lookup("example.com")
+	require.ErrorContains(t, err, "several co-authors")
+
+	_, err = roster.Lookup("carol")
+	require.Error(t, err)
+
+	identity, err = Roster{}.Lookup("Carol Poe <carol@example.com>")
+	require.NoError(t, err)
+	require.Equal(t, "Carol Poe <carol@example.com>", identity, "Full identities need no roster")
+
+	require.NoError(t, os.WriteFile(path, []byte("carol: carol@example.com\n"), 0o644))
+	_, err = LoadRoster(path)
+	require.Error(t, err)
+}
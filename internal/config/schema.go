@@ -0,0 +1,261 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a single schema violation in a config file
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// ValidationError lists every schema violation found in a config file
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, "invalid configuration:")
+	for _, issue := range e.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+type fieldKind int
+
+const (
+	fieldString fieldKind = iota
+	fieldBool
+	fieldStringList
+	fieldMapping
+	// fieldNamedMappings is a mapping of arbitrary names to mappings of elem
+	fieldNamedMappings
+)
+
+func (k fieldKind) String() string {
+	switch k {
+	case fieldBool:
+		return "a boolean"
+	case fieldStringList:
+		return "a list of strings"
+	case fieldMapping, fieldNamedMappings:
+		return "a mapping"
+	default:
+		return "a string"
+	}
+}
+
+type field struct {
+	kind   fieldKind
+	enum   []string
+	fields map[string]*field
+	elem   *field
+}
+
+// settingFields returns the schema of every scalar setting
+func settingFields() map[string]*field {
+	fields := map[string]*field{}
+	for _, s := range Settings {
+		f := &field{kind: fieldString, enum: s.Enum}
+		if s.Kind == KindBool {
+			f.kind = fieldBool
+		}
+		fields[s.Key] = f
+	}
+	return fields
+}
+
+// schema describes the complete layout of a .zeusrc file
+func schema() *field {
+	root := &field{kind: fieldMapping, fields: settingFields()}
+
+	root.fields["policy"] = &field{kind: fieldMapping, fields: map[string]*field{
+		"allowed_providers": {kind: fieldStringList},
+		"network":           {kind: fieldString, enum: []string{NetworkAny, NetworkLocalOnly}},
+	}}
+
+	root.fields["trailers"] = &field{kind: fieldStringList}
+	root.fields["tickets"] = &field{kind: fieldMapping, fields: map[string]*field{
+		"patterns":          {kind: fieldStringList},
+		"placement":         {kind: fieldString, enum: []string{PlacementPrefix, PlacementScope, PlacementTrailer}},
+		"trailer":           {kind: fieldString},
+		"required_branches": {kind: fieldStringList},
+	}}
+
+	root.fields["scopes"] = &field{kind: fieldMapping, fields: map[string]*field{
+		"rules":  {kind: fieldStringList},
+		"detect": {kind: fieldBool},
+	}}
+
+	profile := &field{kind: fieldMapping, fields: settingFields()}
+	profile.fields["branches"] = &field{kind: fieldStringList}
+	profile.fields["paths"] = &field{kind: fieldStringList}
+	root.fields["profiles"] = &field{kind: fieldNamedMappings, elem: profile}
+
+	return root
+}
+
+// ValidateFile checks a config file against the schema. It returns a
+// *ValidationError listing unknown keys, wrong types and invalid values with
+// their line numbers.
+func ValidateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return nil
+	}
+
+	var issues []Issue
+	validateNode(path, "", doc.Content[0], schema(), &issues)
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+func validateNode(file, key string, node *yaml.Node, f *field, issues *[]Issue) {
+	report := func(n *yaml.Node, format string, args ...any) {
+		*issues = append(*issues, Issue{File: file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Null values are treated as unset
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch f.kind {
+	case fieldString:
+		if node.Kind != yaml.ScalarNode {
+			report(node, "%s must be %s", key, f.kind)
+			return
+		}
+		if len(f.enum) > 0 && !contains(f.enum, node.Value) {
+			report(node, "invalid value %q for %s: must be one of %s", node.Value, key, strings.Join(f.enum, ", "))
+		}
+
+	case fieldBool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			report(node, "%s must be %s (true or false), got %q", key, f.kind, node.Value)
+		}
+
+	case fieldStringList:
+		if node.Kind == yaml.ScalarNode {
+			// A single value is accepted in place of a one-element list
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			report(node, "%s must be %s", key, f.kind)
+			return
+		}
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				report(item, "%s must be %s", key, f.kind)
+			}
+		}
+
+	case fieldMapping, fieldNamedMappings:
+		if node.Kind != yaml.MappingNode {
+			report(node, "%s must be %s", displayKey(key), f.kind)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			childKey := joinKey(key, k.Value)
+
+			if f.kind == fieldNamedMappings {
+				validateNode(file, childKey, v, f.elem, issues)
+				continue
+			}
+
+			child, ok := f.fields[k.Value]
+			if !ok {
+				msg := fmt.Sprintf("unknown key %q", childKey)
+				if suggestion := closest(k.Value, f.fields); suggestion != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", joinKey(key, suggestion))
+				}
+				report(k, "%s", msg)
+				continue
+			}
+			validateNode(file, childKey, v, child, issues)
+		}
+	}
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func displayKey(key string) string {
+	if key == "" {
+		return "the top level"
+	}
+	return key
+}
+
+// closest returns the known key nearest to key, if any is close enough to be a typo
+func closest(key string, fields map[string]*field) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDist := "", 3
+	for _, name := range names {
+		if d := levenshtein(key, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// IsValidationError reports whether err is a schema validation failure
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFileReportsIssues(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-config-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, ".zeusrc")
+	configContent := `provder: openrouter
+auto_stage: yes
+default_style: fancy
+policy:
+  network: cloud
+profiles:
+  fast:
+    modle: mistral
+`
+	require.NoError(t, os.WriteFile(path, []byte(configContent), 0o644))
+
+	err = ValidateFile(path)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "Expected a validation error")
+	require.Len(t, validationErr.Issues, 5)
+
+	require.Equal(t, 1, validationErr.Issues[0].Line)
+	require.Contains(t, validationErr.Issues[0].Message, `did you mean "provider"`)
+	require.Equal(t, 2, validationErr.Issues[1].Line)
+	require.Contains(t, validationErr.Issues[1].Message, "must be a boolean")
+	require.Equal(t, 3, validationErr.Issues[2].Line)
+	require.Contains(t, validationErr.Issues[2].Message, "default_style")
+	require.Equal(t, 5, validationErr.Issues[3].Line)
+	require.Equal(t, 8, validationErr.Issues[4].Line)
+	require.Contains(t, validationErr.Issues[4].Message, "profiles.fast.modle")
+}
+
+func TestLoadFailsOnUnknownKey(t *testing.T) {
+	setupConfigDirs(t, "", "provder: openrouter\n")
+
+	_, err := Load()
+	require.True(t, IsValidationError(err), "Expected Load to reject unknown keys")
+	require.ErrorContains(t, err, ".zeusrc:1:1")
+}
+
+func TestLoadEditorReadsInvalidFile(t *testing.T) {
+	setupConfigDirs(t, "", "editor: nano\nprovder: openrouter\nauto_stage: sometimes\n")
+	t.Setenv("ZEUS_EDITOR", "")
+
+	_, err := Load()
+	require.True(t, IsValidationError(err))
+	require.Equal(t, "nano", LoadEditor(), "The editor is read without validating the file")
+
+	require.NoError(t, os.WriteFile(".zeusrc", []byte("editor: [unclosed\n"), 0o644))
+	require.Empty(t, LoadEditor(), "Files that cannot be parsed are skipped")
+
+	t.Setenv("ZEUS_EDITOR", "vim")
+	require.Equal(t, "vim", LoadEditor())
+}
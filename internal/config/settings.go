@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of value a setting holds
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindBool
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// Setting describes a single configuration key, the environment variable that
+// overrides it and the command-line flag, if any, that overrides both.
+type Setting struct {
+	Key         string
+	Flag        string
+	Kind        Kind
+	Enum        []string
+	Description string
+	// UserOnly settings are refused in the repository's .zeusrc and its
+	// profiles, since a cloned repository could otherwise run commands or
+	// send local files to a host of its choosing
+	UserOnly bool
+
+	str     func(*Config) *string
+	boolean func(*Config) *bool
+}
+
+// Env returns the environment variable that overrides the setting
+func (s Setting) Env() string {
+	return "ZEUS_" + strings.ToUpper(s.Key)
+}
+
+// Get returns the setting's current value in cfg formatted as a string
+func (s Setting) Get(cfg *Config) string {
+	if s.Kind == KindBool {
+		return strconv.FormatBool(*s.boolean(cfg))
+	}
+	return *s.str(cfg)
+}
+
+// Set parses value and stores it in cfg
+func (s Setting) Set(cfg *Config, value string) error {
+	if s.Kind == KindBool {
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: expected true or false", value, s.Key)
+		}
+		*s.boolean(cfg) = b
+		return nil
+	}
+
+	if len(s.Enum) > 0 && !contains(s.Enum, value) {
+		return fmt.Errorf("invalid value %q for %s: must be one of %s", value, s.Key, strings.Join(s.Enum, ", "))
+	}
+	*s.str(cfg) = value
+	return nil
+}
+
+// Settings lists every supported configuration key
+var Settings = []Setting{
+	{
+		Key: "provider", Flag: "provider", Kind: KindString,
+		Description: "LLM provider (ollama, openrouter)",
+		str:         func(c *Config) *string { return &c.Provider },
+	},
+	{
+		Key: "api_key", Flag: "api-key", Kind: KindString,
+		Description: "API key for the provider",
+		str:         func(c *Config) *string { return &c.APIKey },
+	},
+	{
+		Key: "api_key_cmd", Kind: KindString, UserOnly: true,
+		Description: "Command that prints the API key, e.g. pass show openrouter",
+		str:         func(c *Config) *string { return &c.APIKeyCmd },
+	},
+	{
+		Key: "api_key_file", Kind: KindString, UserOnly: true,
+		Description: "File containing the API key",
+		str:         func(c *Config) *string { return &c.APIKeyFile },
+	},
+	{
+		Key: "model", Flag: "model", Kind: KindString,
+		Description: "Model to use",
+		str:         func(c *Config) *string { return &c.Model },
+	},
+	{
+		Key: "base_url", Kind: KindString, UserOnly: true,
+		Description: "Override the provider API endpoint",
+		str:         func(c *Config) *string { return &c.BaseURL },
+	},
+	{
+		Key: "default_style", Flag: "style", Kind: KindString,
+		Enum:        []string{"conventional", "simple"},
+		Description: "Commit message style",
+		str:         func(c *Config) *string { return &c.DefaultStyle },
+	},
+	{
+		Key: "include_body", Flag: "body", Kind: KindBool,
+		Description: "Include detailed body text in suggestions",
+		boolean:     func(c *Config) *bool { return &c.IncludeBody },
+	},
+	{
+		Key: "always_edit", Flag: "edit", Kind: KindBool,
+		Description: "Open the selected message in the editor",
+		boolean:     func(c *Config) *bool { return &c.AlwaysEdit },
+	},
+	{
+		Key: "editor", Kind: KindString,
+		Description: "Editor command, overrides $EDITOR",
+		str:         func(c *Config) *string { return &c.Editor },
+	},
+	{
+		Key: "signoff", Flag: "signoff", Kind: KindBool,
+		Description: "Add a Signed-off-by trailer to commits",
+		boolean:     func(c *Config) *bool { return &c.Signoff },
+	},
+	{
+		Key: "sign_by_default", Flag: "sign", Kind: KindBool,
+		Description: "Deprecated alias of signoff",
+		boolean:     func(c *Config) *bool { return &c.Signoff },
+	},
+	{
+		Key: "gpg_sign", Flag: "gpg-sign", Kind: KindString,
+		Description: "Sign commits with GPG or SSH: true, false or a key ID (unset follows commit.gpgsign)",
+		str:         func(c *Config) *string { return &c.GPGSign },
+	},
+	{
+		Key: "branch_pattern", Flag: "pattern", Kind: KindString,
+		Description: "Branch name pattern for zeusctl branch, using {type}, {ticket} and {slug}",
+		str:         func(c *Config) *string { return &c.BranchPattern },
+	},
+	{
+		Key: "review_fail_on", Flag: "fail-on", Kind: KindString,
+		Enum:        []string{"none", "info", "low", "medium", "high", "critical"},
+		Description: "Lowest severity of review findings that makes zeusctl review fail",
+		str:         func(c *Config) *string { return &c.ReviewFailOn },
+	},
+	{
+		Key: "review_fail_on_error", Flag: "fail-on-error", Kind: KindBool,
+		Description: "Fail zeusctl review with review_fail_on set when the provider cannot be reached, instead of skipping the review",
+		boolean:     func(c *Config) *bool { return &c.ReviewFailOnError },
+	},
+	{
+		Key: "roster", Kind: KindString,
+		Description: "Team roster file mapping handles to co-authors, relative to the repository root",
+		str:         func(c *Config) *string { return &c.Roster },
+	},
+	{
+		Key: "auto_stage", Flag: "auto-stage", Kind: KindBool,
+		Description: "Stage all changes before generating suggestions",
+		boolean:     func(c *Config) *bool { return &c.AutoStage },
+	},
+	{
+		Key: "dry_run", Flag: "dry-run", Kind: KindBool,
+		Description: "Show suggestions without committing",
+		boolean:     func(c *Config) *bool { return &c.DryRun },
+	},
+	{
+		Key: "audit", Kind: KindBool,
+		Description: "Record provider requests in the audit log",
+		boolean:     func(c *Config) *bool { return &c.Audit },
+	},
+	{
+		Key: "usage_ledger", Kind: KindBool,
+		Description: "Record token usage and cost in the local ledger read by zeusctl usage",
+		boolean:     func(c *Config) *bool { return &c.UsageLedger },
+	},
+}
+
+// LookupSetting returns the setting with the given key
+func LookupSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Commit is a commit message parsed according to Conventional Commits
+type Commit struct {
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	// Breaking is set by a "!" after the type or scope, or by a
+	// BREAKING CHANGE footer
+	Breaking bool
+	// BreakingNote is the text of the BREAKING CHANGE footer, if any
+	BreakingNote string
+	// Footers holds the trailers at the end of the message, in order
+	Footers []Footer
+}
+
+// Footer is a "Token: value" or "Token #value" line at the end of a message
+type Footer struct {
+	Token string
+	Value string
+}
+
+// Types are the commit types zeus suggests, in the order changelogs list them
+var Types = []string{"feat", "fix", "perf", "refactor", "revert", "docs", "style", "test", "build", "ci", "chore"}
+
+var (
+	header = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+	footer = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)(.*)$`)
+)
+
+// Parse reads a commit message. It reports false when the title does not
+// follow the "type(scope)!: description" format.
+func Parse(message string) (Commit, bool) {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	title, rest, _ := strings.Cut(message, "\n")
+
+	m := header.FindStringSubmatch(strings.TrimSpace(title))
+	if m == nil {
+		return Commit{Description: strings.TrimSpace(title), Body: strings.TrimSpace(rest)}, false
+	}
+
+	c := Commit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] == "!",
+		Description: strings.TrimSpace(m[4]),
+	}
+
+	body, footers := splitFooters(strings.TrimSpace(rest))
+	c.Body, c.Footers = body, footers
+	for _, f := range footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			c.Breaking = true
+			c.BreakingNote = f.Value
+		}
+	}
+	return c, true
+}
+
+// splitFooters separates the last paragraph of text when every line of it
+// starts a footer or continues the previous one
+func splitFooters(text string) (string, []Footer) {
+	if text == "" {
+		return "", nil
+	}
+
+	paragraphs := strings.Split(text, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	var footers []Footer
+	for _, line := range strings.Split(last, "\n") {
+		if m := footer.FindStringSubmatch(line); m != nil {
+			footers = append(footers, Footer{Token: m[1], Value: strings.TrimSpace(m[2])})
+			continue
+		}
+		if len(footers) == 0 {
+			return text, nil
+		}
+		// Continuation lines belong to the previous footer
+		f := &footers[len(footers)-1]
+		f.Value = strings.TrimSpace(f.Value + "\n" + strings.TrimSpace(line))
+	}
+
+	body := strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+	return body, footers
+}
+
+// MarkBreaking adds a "!" to the title of a message that does not mark a
+// breaking change yet. It reports false when the title is not conventional.
+func MarkBreaking(message string) (string, bool) {
+	c, ok := Parse(message)
+	if !ok {
+		return message, false
+	}
+	if c.Breaking {
+		return message, true
+	}
+
+	title, rest, hasRest := strings.Cut(strings.TrimLeft(message, "\n"), "\n")
+	m := header.FindStringSubmatchIndex(strings.TrimSpace(title))
+	// The description starts after ": "
+	colon := strings.Index(title, strings.TrimSpace(title)) + m[8] - 2
+	title = title[:colon] + "!" + title[colon:]
+
+	if hasRest {
+		return title + "\n" + rest, true
+	}
+	return title, true
+}
+
+// WithScope replaces the scope in the title of a message, removing it when
+// scope is empty. It reports false when the title is not conventional.
+func WithScope(message, scope string) (string, bool) {
+	if _, ok := Parse(message); !ok {
+		return message, false
+	}
+
+	title, rest, hasRest := strings.Cut(strings.TrimLeft(message, "\n"), "\n")
+	title = strings.TrimSpace(title)
+	m := header.FindStringSubmatch(title)
+	if scope == "" {
+		title = fmt.Sprintf("%s%s: %s", m[1], m[3], m[4])
+	} else {
+		title = fmt.Sprintf("%s(%s)%s: %s", m[1], scope, m[3], m[4])
+	}
+
+	if hasRest {
+		return title + "\n" + rest, true
+	}
+	return title, true
+}
@@ -0,0 +1,79 @@
+package conventional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	c, ok := Parse("feat(api)!: drop v1 endpoints\n\nThe v1 API is gone.\n\nRefs: PAY-12\nBREAKING CHANGE: clients must use /v2\n  from now on")
+	require.True(t, ok)
+	require.Equal(t, "feat", c.Type)
+	require.Equal(t, "api", c.Scope)
+	require.Equal(t, "drop v1 endpoints", c.Description)
+	require.Equal(t, "The v1 API is gone.", c.Body)
+	require.True(t, c.Breaking)
+	require.Equal(t, "clients must use /v2\nfrom now on", c.BreakingNote)
+	require.Equal(t, []Footer{
+		{Token: "Refs", Value: "PAY-12"},
+		{Token: "BREAKING CHANGE", Value: "clients must use /v2\nfrom now on"},
+	}, c.Footers)
+}
+
+func TestParseBreakingFooterOnly(t *testing.T) {
+	c, ok := Parse("Fix: handle nil\n\nBREAKING-CHANGE: nil now errors")
+	require.True(t, ok)
+	require.Equal(t, "fix", c.Type)
+	require.True(t, c.Breaking)
+	require.Equal(t, "nil now errors", c.BreakingNote)
+}
+
+func TestParseBodyWithoutFooters(t *testing.T) {
+	c, ok := Parse("docs: explain setup\n\nFirst paragraph.\n\nSecond paragraph\nwith two lines.")
+	require.True(t, ok)
+	require.False(t, c.Breaking)
+	require.Empty(t, c.Footers)
+	require.Equal(t, "First paragraph.\n\nSecond paragraph\nwith two lines.", c.Body)
+}
+
+func TestParseNonConventional(t *testing.T) {
+	c, ok := Parse("Update readme\n\nMore words.")
+	require.False(t, ok)
+	require.Equal(t, "Update readme", c.Description)
+	require.Equal(t, "More words.", c.Body)
+
+	_, ok = Parse("feat:missing space")
+	require.False(t, ok)
+}
+
+func TestMarkBreaking(t *testing.T) {
+	for message, want := range map[string]string{
+		"feat: drop v1":                       "feat!: drop v1",
+		"feat(api): drop v1\n\nBody: text":    "feat(api)!: drop v1\n\nBody: text",
+		"feat(api)!: drop v1":                 "feat(api)!: drop v1",
+		"fix: x\n\nBREAKING CHANGE: y":        "fix: x\n\nBREAKING CHANGE: y",
+		"refactor(a: b): keep colons: intact": "refactor(a: b)!: keep colons: intact",
+	} {
+		got, ok := MarkBreaking(message)
+		require.True(t, ok, message)
+		require.Equal(t, want, got)
+	}
+
+	got, ok := MarkBreaking("Drop v1")
+	require.False(t, ok)
+	require.Equal(t, "Drop v1", got)
+}
+
+func TestWithScope(t *testing.T) {
+	got, ok := WithScope("feat(ui)!: add invoices\n\nBody.", "billing")
+	require.True(t, ok)
+	require.Equal(t, "feat(billing)!: add invoices\n\nBody.", got)
+
+	got, ok = WithScope("fix: round totals", "")
+	require.True(t, ok)
+	require.Equal(t, "fix: round totals", got)
+
+	_, ok = WithScope("Round totals", "billing")
+	require.False(t, ok)
+}
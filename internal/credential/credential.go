@@ -0,0 +1,117 @@
+package credential
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+)
+
+// ErrNotFound is returned by a Store that holds no key for the provider
+var ErrNotFound = errors.New("credential not found")
+
+// Store keeps API keys outside of config files
+type Store interface {
+	Name() string
+	Get(provider string) (string, error)
+	Set(provider, key string) error
+	Delete(provider string) error
+}
+
+// Source describes where an API key was found
+type Source string
+
+const (
+	SourceNone    Source = "none"
+	SourceConfig  Source = "api_key"
+	SourceCommand Source = "api_key_cmd"
+	SourceFile    Source = "api_key_file"
+	SourceKeyring Source = "keyring"
+)
+
+// Resolve returns the API key for the configured provider. An explicit api_key
+// (from flags, environment or config) wins, followed by api_key_cmd,
+// api_key_file and finally the OS keyring.
+func Resolve(cfg *config.Config, store Store) (string, Source, error) {
+	if cfg.APIKey != "" {
+		return cfg.APIKey, SourceConfig, nil
+	}
+
+	if cfg.APIKeyCmd != "" {
+		key, err := runKeyCommand(cfg.APIKeyCmd)
+		if err != nil {
+			return "", SourceCommand, err
+		}
+		return key, SourceCommand, nil
+	}
+
+	if cfg.APIKeyFile != "" {
+		key, err := readKeyFile(cfg.APIKeyFile)
+		if err != nil {
+			return "", SourceFile, err
+		}
+		return key, SourceFile, nil
+	}
+
+	if store != nil {
+		key, err := store.Get(strings.ToLower(cfg.Provider))
+		if err == nil {
+			return key, SourceKeyring, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", SourceKeyring, err
+		}
+	}
+
+	return "", SourceNone, nil
+}
+
+func runKeyCommand(command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.Command(shell, flag, command) // #nosec G204 -- api_key_cmd is only read from ~/.zeusrc, the environment and flags
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("api_key_cmd %q failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Password managers such as pass print the secret on the first line
+	key := strings.TrimSpace(strings.SplitN(stdout.String(), "\n", 2)[0])
+	if key == "" {
+		return "", fmt.Errorf("api_key_cmd %q printed no key", command)
+	}
+	return key, nil
+}
+
+func readKeyFile(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read api_key_file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("api_key_file %s is empty", path)
+	}
+	return key, nil
+}
@@ -0,0 +1,78 @@
+package credential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+)
+
+type memoryStore map[string]string
+
+func (m memoryStore) Name() string { return "memory" }
+
+func (m memoryStore) Get(provider string) (string, error) {
+	key, ok := m[provider]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (m memoryStore) Set(provider, key string) error {
+	m[provider] = key
+	return nil
+}
+
+func (m memoryStore) Delete(provider string) error {
+	delete(m, provider)
+	return nil
+}
+
+func TestResolveOrder(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-credential-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	keyFile := filepath.Join(tmpDir, "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("file-key\n"), 0o600))
+
+	store := memoryStore{"openrouter": "keyring-key"}
+	cfg := &config.Config{Provider: "openrouter"}
+
+	key, source, err := Resolve(cfg, store)
+	require.NoError(t, err)
+	require.Equal(t, "keyring-key", key)
+	require.Equal(t, SourceKeyring, source)
+
+	cfg.APIKeyFile = keyFile
+	key, source, err = Resolve(cfg, store)
+	require.NoError(t, err)
+	require.Equal(t, "file-key", key)
+	require.Equal(t, SourceFile, source)
+
+	cfg.APIKeyCmd = "echo cmd-key"
+	key, source, err = Resolve(cfg, store)
+	require.NoError(t, err)
+	require.Equal(t, "cmd-key", key)
+	require.Equal(t, SourceCommand, source)
+
+	cfg.APIKey = "explicit-key"
+	key, source, err = Resolve(cfg, store)
+	require.NoError(t, err)
+	require.Equal(t, "explicit-key", key)
+	require.Equal(t, SourceConfig, source)
+}
+
+func TestResolveCommandFailure(t *testing.T) {
+	cfg := &config.Config{Provider: "openrouter", APIKeyCmd: "exit 3"}
+	_, _, err := Resolve(cfg, nil)
+	require.ErrorContains(t, err, "api_key_cmd")
+
+	_, source, err := Resolve(&config.Config{Provider: "openrouter"}, memoryStore{})
+	require.NoError(t, err)
+	require.Equal(t, SourceNone, source)
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+package credential
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const keyringService = "zeus-ai"
+
+// secretServiceStore stores keys in the freedesktop Secret Service (GNOME
+// Keyring, KWallet) through libsecret's secret-tool.
+type secretServiceStore struct{}
+
+// DefaultStore returns the OS keyring backend
+func DefaultStore() (Store, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, errors.New("secret-tool not found: install libsecret-tools to use the keyring")
+	}
+	return secretServiceStore{}, nil
+}
+
+func (secretServiceStore) Name() string {
+	return "Secret Service"
+}
+
+func (secretServiceStore) Get(provider string) (string, error) {
+	cmd := exec.Command("secret-tool", "lookup", "service", keyringService, "provider", provider)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && stderr.Len() == 0 {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("keyring lookup failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (secretServiceStore) Set(provider, key string) error {
+	cmd := exec.Command("secret-tool", "store",
+		"--label", fmt.Sprintf("zeus-ai %s API key", provider),
+		"service", keyringService, "provider", provider)
+	cmd.Stdin = strings.NewReader(key)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("keyring store failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (secretServiceStore) Delete(provider string) error {
+	if _, err := (secretServiceStore{}).Get(provider); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("secret-tool", "clear", "service", keyringService, "provider", provider)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("keyring clear failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
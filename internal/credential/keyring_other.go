@@ -0,0 +1,13 @@
+//go:build !linux
+
+package credential
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// DefaultStore returns the OS keyring backend
+func DefaultStore() (Store, error) {
+	return nil, fmt.Errorf("keyring storage is not supported on %s yet: use api_key_cmd or api_key_file", runtime.GOOS)
+}
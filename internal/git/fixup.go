@@ -0,0 +1,224 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FixupCandidate is an unpushed commit that staged changes may belong to
+type FixupCandidate struct {
+	Commit  string
+	Subject string
+	// BlamedLines counts the lines changed by the staged hunks that git blame
+	// attributes to the commit
+	BlamedLines int
+	// TouchedHunks counts the staged hunks that overlap lines the commit changed
+	TouchedHunks int
+	// SharedFiles counts the staged files the commit also changed
+	SharedFiles int
+}
+
+// Score weighs the evidence that the staged changes belong to the commit.
+// Blamed lines are the strongest signal, a shared file the weakest.
+func (c FixupCandidate) Score() int {
+	return 3*c.BlamedLines + 2*c.TouchedHunks + c.SharedFiles
+}
+
+// lineRange is a span of lines in a file, from Start to End inclusive
+type lineRange struct {
+	File       string
+	Start, End int
+}
+
+// RankFixupCandidates compares hunks against the last limit unpushed,
+// non-merge commits on the current branch. Candidates are returned best
+// first; commits with no evidence at all are left out.
+func RankFixupCandidates(hunks []Hunk, limit int) ([]FixupCandidate, error) {
+	commits, err := UnpushedCommits(limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	candidates := make([]FixupCandidate, len(commits))
+	position := map[string]int{}
+	for i, c := range commits {
+		subject, err := CommitSubject(c)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i] = FixupCandidate{Commit: c, Subject: subject}
+		position[c] = i
+	}
+
+	for _, h := range hunks {
+		// Lines are blamed in HEAD, where renamed files still have their old
+		// path and added files do not exist
+		file := h.OldFile()
+		lines := h.changedOldLines()
+		if file == "" || len(lines) == 0 {
+			continue
+		}
+		blamed, err := blameLines(file, lines)
+		if err != nil {
+			return nil, err
+		}
+		for commit, n := range blamed {
+			if i, ok := position[commit]; ok {
+				candidates[i].BlamedLines += n
+			}
+		}
+	}
+
+	stagedFiles := map[string]bool{}
+	for _, h := range hunks {
+		stagedFiles[h.File] = true
+	}
+
+	for i := range candidates {
+		touched, err := touchedLines(candidates[i].Commit)
+		if err != nil {
+			return nil, err
+		}
+
+		files := map[string]bool{}
+		for _, r := range touched {
+			files[r.File] = true
+		}
+		for file := range stagedFiles {
+			if files[file] {
+				candidates[i].SharedFiles++
+			}
+		}
+
+		for _, h := range hunks {
+			if overlaps(h, touched) {
+				candidates[i].TouchedHunks++
+			}
+		}
+	}
+
+	// Commits are listed newest first, so a stable sort prefers recent ones on ties
+	var ranked []FixupCandidate
+	for _, c := range candidates {
+		if c.Score() > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score() > ranked[j].Score() })
+	return ranked, nil
+}
+
+// UnpushedCommits returns up to limit non-merge commits on the current branch
+// that no remote-tracking branch contains, newest first
+func UnpushedCommits(limit int) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--no-merges", "-n", strconv.Itoa(limit), "HEAD", "--not", "--remotes")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+
+	return strings.Fields(out.String()), nil
+}
+
+// CommitSubject returns the first line of the message of commit
+func CommitSubject(commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%s", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git log failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+var blameHeader = regexp.MustCompile(`^\^?([0-9a-f]{40}) \d+ \d+`)
+
+// blameLines counts, per commit, the given lines of file in HEAD that git
+// blame attributes to it
+func blameLines(file string, lines []int) (map[string]int, error) {
+	args := []string{"blame", "--porcelain"}
+	for _, l := range lines {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", l, l))
+	}
+	args = append(args, "HEAD", "--", file)
+
+	cmd := exec.Command("git", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git blame failed: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if m := blameHeader.FindStringSubmatch(line); m != nil {
+			counts[m[1]]++
+		}
+	}
+	return counts, nil
+}
+
+// touchedLines returns the line ranges commit changed, as they stood right
+// after the commit
+func touchedLines(commit string) ([]lineRange, error) {
+	cmd := exec.Command("git", "show", "--format=", "--unified=0", "--no-color", "--no-ext-diff", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+
+	var ranges []lineRange
+	for _, h := range ParsePatch(out.String()) {
+		r := lineRange{File: h.File}
+		for _, part := range splitHunks(h.Body) {
+			_, _, start, count := parseHunkHeader(part)
+			if start == 0 {
+				continue
+			}
+			r.Start, r.End = start, start+count-1
+			if count == 0 {
+				// A pure deletion happened between start and the next line
+				r.End = start + 1
+			}
+			ranges = append(ranges, r)
+		}
+		if h.Body == "" || isAtomic(h.Header) {
+			// Whole-file changes touch every line
+			ranges = append(ranges, lineRange{File: h.File, Start: 1, End: int(^uint(0) >> 1)})
+		}
+	}
+	return ranges, nil
+}
+
+// overlaps reports whether the lines the hunk changes fall in, or directly
+// next to, one of the ranges. Later commits may have shifted the lines since,
+// so this is an approximation that blame refines.
+func overlaps(h Hunk, ranges []lineRange) bool {
+	start, count := h.OldRange()
+	if start == 0 {
+		return false
+	}
+	end := start + count - 1
+
+	for _, r := range ranges {
+		if r.File == h.OldFile() && start <= r.End+1 && r.Start-1 <= end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankFixupCandidates(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+
+	lines[15] = "parser"
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("feat: add parser", CommitOptions{}))
+
+	createAndAddFile(t, tmpDir, "other.txt", "other\n")
+	require.NoError(t, Commit("docs: add other", CommitOptions{}))
+
+	// Fix the line the parser commit introduced
+	lines[15] = "parser fixed"
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+
+	patch, err := GetStagedPatch()
+	require.NoError(t, err)
+	candidates, err := RankFixupCandidates(ParsePatch(patch), 10)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, candidates)
+	require.Equal(t, "feat: add parser", candidates[0].Subject)
+	require.Equal(t, 1, candidates[0].BlamedLines)
+	require.Equal(t, 1, candidates[0].TouchedHunks)
+	for _, c := range candidates {
+		require.NotEqual(t, "docs: add other", c.Subject, "Commits without evidence are left out")
+	}
+
+	// Only the newest commit is considered with a limit of one
+	candidates, err = RankFixupCandidates(ParsePatch(patch), 1)
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	target, err := ResolveCommit("HEAD~1")
+	require.NoError(t, err)
+	require.NoError(t, Fixup(target, CommitOptions{}))
+	subject, err := CommitSubject("HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "fixup! feat: add parser", subject)
+}
+
+func TestChangedOldLines(t *testing.T) {
+	removal := Hunk{File: "a", Body: "@@ -3,3 +3,2 @@\n a\n-b\n c\n"}
+	require.Equal(t, []int{4}, removal.changedOldLines())
+
+	addition := Hunk{File: "a", Body: "@@ -3,2 +3,3 @@\n a\n+new\n b\n"}
+	require.Equal(t, []int{3, 4}, addition.changedOldLines(), "Additions use the surrounding context")
+
+	whole := Hunk{File: "a", Body: ""}
+	require.Nil(t, whole.changedOldLines())
+}
+
+func TestRankFixupCandidatesWithStagedRename(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	createAndAddFile(t, tmpDir, "old.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+
+	lines[15] = "parser"
+	createAndAddFile(t, tmpDir, "old.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("feat: add parser", CommitOptions{}))
+
+	// Rename the file and fix the parser line in the same staged change
+	gitOutput(t, tmpDir, "mv", "old.txt", "new.txt")
+	lines[15] = "parser fixed"
+	createAndAddFile(t, tmpDir, "new.txt", strings.Join(lines, "\n")+"\n")
+	createAndAddFile(t, tmpDir, "added.txt", "added\n")
+
+	patch, err := GetStagedPatch()
+	require.NoError(t, err)
+	hunks := ParsePatch(patch)
+	for _, h := range hunks {
+		if h.File == "new.txt" {
+			require.Equal(t, "old.txt", h.OldFile())
+		}
+		if h.File == "added.txt" {
+			require.Empty(t, h.OldFile())
+		}
+	}
+
+	candidates, err := RankFixupCandidates(hunks, 10)
+	require.NoError(t, err, "Renamed and added files must not abort the ranking")
+	require.NotEmpty(t, candidates)
+	require.Equal(t, "feat: add parser", candidates[0].Subject)
+	require.Equal(t, 1, candidates[0].BlamedLines)
+}
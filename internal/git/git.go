@@ -2,9 +2,12 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 // IsGitRepository checks if the current directory is a git repository
@@ -14,6 +17,19 @@ func IsGitRepository() bool {
 	return err == nil
 }
 
+// RepoRoot returns the absolute path of the top-level directory of the repository
+func RepoRoot() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
 // GetDiff returns the git diff (staged or unstaged)
 func GetDiff(staged bool) (string, error) {
 	args := []string{"diff"}
@@ -75,20 +91,389 @@ func StageAllChanges() error {
 }
 
 // Commit performs a git commit with the given message
-func Commit(message string, sign bool) error {
-	args := []string{"commit", "-m", message}
-	if sign {
-		args = append(args, "-s")
+func Commit(message string, opts CommitOptions) error {
+	return commit([]string{"commit", "-m", message}, opts)
+}
+
+// Amend replaces the last commit with one including the staged changes and
+// the given message
+func Amend(message string, opts CommitOptions) error {
+	return commit([]string{"commit", "--amend", "-m", message}, opts)
+}
+
+// Fixup commits the staged changes as a "fixup!" commit for target, to be
+// folded into it by git rebase --autosquash
+func Fixup(target string, opts CommitOptions) error {
+	return commit([]string{"commit", "--fixup=" + target}, opts)
+}
+
+func commit(args []string, opts CommitOptions) error {
+	signing, err := GetSigningConfig()
+	if err != nil {
+		return err
+	}
+	if err = signing.Check(opts); err != nil {
+		return err
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := exec.Command("git", append(args, opts.args()...)...)
+	var stderr bytes.Buffer
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
+		if signing.WillSign(opts) {
+			if signErr := signingError(stderr.String(), signing); signErr != nil {
+				return signErr
+			}
+		}
 		return fmt.Errorf("git commit failed: %w", err)
 	}
 
 	return nil
 }
+
+// CurrentBranch returns the name of the checked out branch, or an empty
+// string when HEAD is detached
+func CurrentBranch() (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git symbolic-ref failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// CheckBranchName reports whether name is a valid branch name according to
+// git check-ref-format
+func CheckBranchName(name string) error {
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%q is not a valid branch name", name)
+	}
+
+	return nil
+}
+
+// BranchExists reports whether a local branch with the given name exists
+func BranchExists(name string) (bool, error) {
+	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+name)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git show-ref failed: %w", err)
+}
+
+// CreateBranch creates a branch at HEAD and switches to it. Staged and
+// unstaged changes are carried over to the new branch.
+func CreateBranch(name string) error {
+	cmd := exec.Command("git", "checkout", "--quiet", "-b", name)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// IsTracked reports whether path is tracked in the index
+func IsTracked(path string) (bool, error) {
+	cmd := exec.Command("git", "ls-files", "--error-unmatch", "--", path)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git ls-files failed: %w", err)
+}
+
+// IsIgnored reports whether path is excluded by .gitignore or another exclude file
+func IsIgnored(path string) (bool, error) {
+	cmd := exec.Command("git", "check-ignore", "-q", "--", path)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git check-ignore failed: %w", err)
+}
+
+// Version returns the version of the git binary, e.g. "2.39.5"
+func Version() (string, error) {
+	cmd := exec.Command("git", "--version")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git --version failed: %w", err)
+	}
+
+	fields := strings.Fields(out.String())
+	if len(fields) < 3 {
+		return "", fmt.Errorf("unexpected git --version output: %q", out.String())
+	}
+	return fields[2], nil
+}
+
+// emptyTree is the hash of the tree with no entries, used as the parent of root commits
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// GetAmendDiff returns the changes an amended HEAD would contain: the diff of
+// the last commit plus anything staged
+func GetAmendDiff() (string, error) {
+	if _, err := ResolveCommit("HEAD"); err != nil {
+		return "", errors.New("there is no commit to amend")
+	}
+
+	base := emptyTree
+	if parent, err := ResolveCommit("HEAD^"); err == nil {
+		base = parent
+	}
+
+	cmd := exec.Command("git", "diff", "--cached", base)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// ResolveCommit returns the full hash of the commit rev refers to
+func ResolveCommit(rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// CommitParents returns the hashes of the parents of commit
+func CommitParents(commit string) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--parents", "-n", "1", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+
+	fields := strings.Fields(out.String())
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("unknown commit %q", commit)
+	}
+	return fields[1:], nil
+}
+
+// BaseOf returns the first parent of commit, or the empty tree when commit is
+// a root commit, so that diffing from it shows everything commit changed
+func BaseOf(commit string) (string, error) {
+	parents, err := CommitParents(commit)
+	if err != nil {
+		return "", err
+	}
+	if len(parents) == 0 {
+		return emptyTree, nil
+	}
+	return parents[0], nil
+}
+
+// GetCommitDiff returns the changes introduced by commit
+func GetCommitDiff(commit string) (string, error) {
+	cmd := exec.Command("git", "show", "--format=", "--patch", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git show failed: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// ShowFile returns the contents of path, relative to the repository root, at
+// commit rev, or in the index when rev is empty
+func ShowFile(rev, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", rev+":"+path)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// HooksDir returns the directory git runs hooks from, following core.hooksPath
+func HooksDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// IsPushed reports whether commit is reachable from any remote-tracking branch
+func IsPushed(commit string) (bool, error) {
+	cmd := exec.Command("git", "branch", "--remotes", "--contains", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return false, fmt.Errorf("git branch failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()) != "", nil
+}
+
+// Reword replaces the message of commit, which must be an ancestor of HEAD.
+// It recreates the commit with the same tree, parents and author and then
+// replays the commits after it with a non-interactive rebase. The rewritten
+// and replayed commits are signed as opts asks; a sign-off is added to the
+// reworded commit only.
+func Reword(commit, message string, opts CommitOptions) error {
+	signing, err := GetSigningConfig()
+	if err != nil {
+		return err
+	}
+	if err = signing.Check(opts); err != nil {
+		return err
+	}
+
+	parents, err := CommitParents(commit)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%an%x00%ae%x00%ad", "--date=raw", commit)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("git log failed: %w", err)
+	}
+	author := strings.SplitN(strings.TrimSpace(out.String()), "\x00", 3)
+	if len(author) != 3 {
+		return fmt.Errorf("failed to read the author of %s", commit)
+	}
+
+	if opts.Signoff {
+		if message, err = signoff(message); err != nil {
+			return err
+		}
+	}
+
+	// Unlike git commit and git rebase, git commit-tree ignores commit.gpgsign
+	treeOpts := opts
+	if treeOpts.GPGSign == "" && signing.WillSign(opts) {
+		treeOpts.GPGSign = "true"
+	}
+	args := []string{"commit-tree", commit + "^{tree}", "-F", "-"}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+	cmd = exec.Command("git", append(args, treeOpts.gpgArgs()...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+author[0],
+		"GIT_AUTHOR_EMAIL="+author[1],
+		"GIT_AUTHOR_DATE="+author[2],
+	)
+	cmd.Stdin = strings.NewReader(message)
+	out.Reset()
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	if err = cmd.Run(); err != nil {
+		if signing.WillSign(opts) {
+			if signErr := signingError(stderr.String(), signing); signErr != nil {
+				return signErr
+			}
+		}
+		return fmt.Errorf("git commit-tree failed: %w", err)
+	}
+	rewritten := strings.TrimSpace(out.String())
+
+	args = []string{"rebase", "--quiet", "--autostash", "--rebase-merges"}
+	args = append(args, opts.gpgArgs()...)
+	cmd = exec.Command("git", append(args, "--onto", rewritten, commit)...)
+	stderr.Reset()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	if err = cmd.Run(); err != nil {
+		if signing.WillSign(opts) {
+			if signErr := signingError(stderr.String(), signing); signErr != nil {
+				return signErr
+			}
+		}
+		return fmt.Errorf("git rebase failed: %w", err)
+	}
+
+	return nil
+}
+
+// signoff adds the committer's Signed-off-by trailer to message, as
+// git commit --signoff does
+func signoff(message string) (string, error) {
+	cmd := exec.Command("git", "var", "GIT_COMMITTER_IDENT")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git var failed: %w", err)
+	}
+	// The identity ends with the timestamp and time zone
+	ident := strings.TrimSpace(out.String())
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+	return AddTrailers(message, []Trailer{{Key: "Signed-off-by", Value: ident}})
+}
+
+// IsAncestor reports whether commit is an ancestor of, or the same as, rev
+func IsAncestor(commit, rev string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", commit, rev)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git merge-base failed: %w", err)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -224,7 +225,7 @@ func TestCommit(t *testing.T) {
 
 	// Make a commit
 	commitMsg := "Test commit message"
-	err = Commit(commitMsg, false)
+	err = Commit(commitMsg, CommitOptions{})
 	require.NoError(t, err, "Failed to commit")
 
 	// Verify the commit was made
@@ -268,7 +269,7 @@ func TestSignedCommit(t *testing.T) {
 
 	// Make a signed commit
 	commitMsg := "Signed commit message"
-	err = Commit(commitMsg, true)
+	err = Commit(commitMsg, CommitOptions{})
 	require.NoError(t, err, "Failed to create signed commit")
 
 	// Verify the commit was made and signed
@@ -278,3 +279,239 @@ func TestSignedCommit(t *testing.T) {
 	require.NoError(t, err, "Failed to get git log with signature")
 	require.Contains(t, string(output), commitMsg, "Expected git log to contain commit message")
 }
+
+// gitOutput runs a git command in repoDir and returns its trimmed output
+func gitOutput(t *testing.T, repoDir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	require.NoError(t, err, "git %v failed", args)
+	return strings.TrimSpace(string(output))
+}
+
+func TestAmend(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	_, err = GetAmendDiff()
+	require.Error(t, err, "Expected an error without any commit")
+
+	// The root commit is diffed against the empty tree
+	createAndAddFile(t, tmpDir, "first.txt", "first")
+	require.NoError(t, Commit("first", CommitOptions{}))
+	diff, err := GetAmendDiff()
+	require.NoError(t, err)
+	require.Contains(t, diff, "+first")
+
+	createAndAddFile(t, tmpDir, "second.txt", "second")
+	require.NoError(t, Commit("second", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "staged.txt", "staged")
+
+	diff, err = GetAmendDiff()
+	require.NoError(t, err)
+	require.Contains(t, diff, "second.txt", "Expected the diff of the last commit")
+	require.Contains(t, diff, "staged.txt", "Expected the staged changes")
+	require.NotContains(t, diff, "first.txt", "Expected earlier commits to be excluded")
+
+	require.NoError(t, Amend("second, amended", CommitOptions{}))
+	require.Equal(t, "second, amended\nfirst", gitOutput(t, tmpDir, "log", "--format=%s"))
+	require.Contains(t, gitOutput(t, tmpDir, "show", "--name-only", "--format="), "staged.txt")
+}
+
+func TestReword(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	for _, name := range []string{"one", "two", "three"} {
+		createAndAddFile(t, tmpDir, name+".txt", name)
+		require.NoError(t, Commit(name, CommitOptions{}))
+	}
+
+	target, err := ResolveCommit("HEAD~1")
+	require.NoError(t, err)
+
+	diff, err := GetCommitDiff(target)
+	require.NoError(t, err)
+	require.Contains(t, diff, "two.txt")
+	require.NotContains(t, diff, "three.txt")
+
+	// Simulate a push of the first commit only
+	gitOutput(t, tmpDir, "update-ref", "refs/remotes/origin/main", "HEAD~2")
+	pushed, err := IsPushed(target)
+	require.NoError(t, err)
+	require.False(t, pushed)
+	pushed, err = IsPushed("HEAD~2")
+	require.NoError(t, err)
+	require.True(t, pushed)
+
+	// Leave an unstaged change that has to survive the rebase
+	err = os.WriteFile(filepath.Join(tmpDir, "three.txt"), []byte("dirty"), 0o644)
+	require.NoError(t, err, "Failed to write file")
+
+	require.NoError(t, Reword(target, "feat: second commit\n\nWith a body.", CommitOptions{}))
+
+	require.Equal(t, "three\nfeat: second commit\none", gitOutput(t, tmpDir, "log", "--format=%s"))
+	require.Equal(t, "With a body.", gitOutput(t, tmpDir, "log", "-1", "--format=%b", "HEAD~1"))
+	require.Equal(t, "Test User <test@example.com>", gitOutput(t, tmpDir, "log", "-1", "--format=%an <%ae>", "HEAD~1"))
+	require.Contains(t, gitOutput(t, tmpDir, "show", "--name-only", "--format=", "HEAD~1"), "two.txt")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "three.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "dirty", string(content))
+}
+
+func TestRewordSigns(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("Skipping as ssh-keygen is not installed")
+	}
+
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	for _, name := range []string{"one", "two", "three"} {
+		createAndAddFile(t, tmpDir, name+".txt", name)
+		require.NoError(t, Commit(name, CommitOptions{}))
+	}
+
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).Run())
+	gitOutput(t, tmpDir, "config", "gpg.format", "ssh")
+
+	// SSH signing without a key is refused before anything is rewritten
+	err = Reword("HEAD~1", "feat: second commit", CommitOptions{GPGSign: "true"})
+	require.ErrorContains(t, err, "user.signingkey")
+	require.Equal(t, "three\ntwo\none", gitOutput(t, tmpDir, "log", "--format=%s"))
+
+	require.NoError(t, Reword("HEAD~1", "feat: second commit", CommitOptions{Signoff: true, GPGSign: key + ".pub"}))
+
+	require.Equal(t, "three\nfeat: second commit\none", gitOutput(t, tmpDir, "log", "--format=%s"))
+	require.Equal(t, "Signed-off-by: Test User <test@example.com>", gitOutput(t, tmpDir, "log", "-1", "--format=%b", "HEAD~1"))
+	require.Empty(t, gitOutput(t, tmpDir, "log", "-1", "--format=%b", "HEAD"), "Only the reworded commit is signed off")
+	require.Contains(t, gitOutput(t, tmpDir, "cat-file", "commit", "HEAD~1"), "gpgsig ", "Expected the reworded commit to be signed")
+	require.Contains(t, gitOutput(t, tmpDir, "cat-file", "commit", "HEAD"), "gpgsig ", "Expected the replayed commit to be signed")
+}
+
+func TestRewordFollowsCommitGPGSign(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	for _, name := range []string{"one", "two", "three"} {
+		createAndAddFile(t, tmpDir, name+".txt", name)
+		require.NoError(t, Commit(name, CommitOptions{}))
+	}
+
+	// A stand-in for gpg that reports success and prints a fixed signature
+	program := filepath.Join(t.TempDir(), "gpg")
+	script := "#!/bin/sh\ncat >/dev/null\nprintf '\\n[GNUPG:] SIG_CREATED D 22 8 00 0 STUB\\n' >&2\n" +
+		"printf -- '-----BEGIN PGP SIGNATURE-----\\n\\nstub\\n-----END PGP SIGNATURE-----\\n'\n"
+	require.NoError(t, os.WriteFile(program, []byte(script), 0o755))
+	gitOutput(t, tmpDir, "config", "gpg.program", program)
+	gitOutput(t, tmpDir, "config", "commit.gpgsign", "true")
+
+	require.NoError(t, Reword("HEAD~1", "feat: second commit", CommitOptions{}))
+
+	require.Equal(t, "three\nfeat: second commit\none", gitOutput(t, tmpDir, "log", "--format=%s"))
+	require.Contains(t, gitOutput(t, tmpDir, "cat-file", "commit", "HEAD~1"), "gpgsig ", "Expected commit.gpgsign to sign the reworded commit")
+	require.Contains(t, gitOutput(t, tmpDir, "cat-file", "commit", "HEAD"), "gpgsig ", "Expected the replayed commit to be signed")
+	require.NotContains(t, gitOutput(t, tmpDir, "cat-file", "commit", "HEAD~2"), "gpgsig ", "Earlier commits are left alone")
+}
+
+func TestCreateBranchCarriesChanges(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	createAndAddFile(t, tmpDir, "file.txt", "initial\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "file.txt", "changed\n")
+
+	require.NoError(t, CheckBranchName("feat/refund-flow"))
+	require.Error(t, CheckBranchName("feat/refund flow"))
+	require.Error(t, CheckBranchName("feat..x"))
+
+	exists, err := BranchExists("feat/refund-flow")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, CreateBranch("feat/refund-flow"))
+	branch, err := CurrentBranch()
+	require.NoError(t, err)
+	require.Equal(t, "feat/refund-flow", branch)
+
+	exists, err = BranchExists("feat/refund-flow")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	diff, err := GetDiff(true)
+	require.NoError(t, err)
+	require.Contains(t, diff, "+changed", "Staged changes should be carried over")
+
+	require.Error(t, CreateBranch("feat/refund-flow"), "Existing branches are not overwritten")
+}
+
+func TestShowFile(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	createAndAddFile(t, tmpDir, "file.txt", "committed\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "file.txt", "staged\n")
+
+	data, err := ShowFile("HEAD", "file.txt")
+	require.NoError(t, err)
+	require.Equal(t, "committed\n", string(data))
+
+	data, err = ShowFile("", "file.txt")
+	require.NoError(t, err)
+	require.Equal(t, "staged\n", string(data), "An empty revision reads the index")
+
+	_, err = ShowFile("HEAD", "missing.txt")
+	require.Error(t, err)
+}
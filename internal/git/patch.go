@@ -0,0 +1,403 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Hunk is a part of a staged change that can be committed on its own. Files
+// that are added, deleted, renamed, copied or binary form a single hunk with
+// all of their changes, since git cannot apply them piecemeal.
+type Hunk struct {
+	// Index is the position of the hunk in the diff, starting at 0
+	Index int
+	File  string
+	// Header holds the file header lines, from "diff --git" up to the first "@@"
+	Header string
+	// Body holds the hunk itself, starting with its "@@" line
+	Body string
+}
+
+// Summary returns the file and the "@@" line of the hunk
+func (h Hunk) Summary() string {
+	line, _, _ := strings.Cut(h.Body, "\n")
+	if line == "" {
+		return h.File
+	}
+	return h.File + " " + line
+}
+
+// OldFile returns the path of the hunk's file before the change, which
+// differs from File for renames, or an empty string for added files
+func (h Hunk) OldFile() string {
+	for _, f := range DiffFiles(h.Header) {
+		return f.OldPath
+	}
+	return h.File
+}
+
+// GetStagedPatch returns the staged changes as a patch that git apply accepts,
+// including binary files
+func GetStagedPatch() (string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--binary", "--no-color", "--no-ext-diff")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// ParsePatch splits a patch produced by git diff into hunks
+func ParsePatch(patch string) []Hunk {
+	var hunks []Hunk
+
+	for _, block := range splitFiles(patch) {
+		header, body := block, ""
+		if i := strings.Index(block, "\n@@"); i >= 0 {
+			header, body = block[:i+1], block[i+1:]
+		}
+		file := patchPath(header)
+
+		if body == "" || isAtomic(header) {
+			hunks = append(hunks, Hunk{Index: len(hunks), File: file, Header: header, Body: body})
+			continue
+		}
+
+		for _, part := range splitHunks(body) {
+			hunks = append(hunks, Hunk{Index: len(hunks), File: file, Header: header, Body: part})
+		}
+	}
+
+	return hunks
+}
+
+// FileChange is a file touched by a diff. OldPath is empty for added files and
+// NewPath for deleted ones.
+type FileChange struct {
+	OldPath string
+	NewPath string
+}
+
+// DiffFiles lists the files a diff touches
+func DiffFiles(diff string) []FileChange {
+	var files []FileChange
+	for _, block := range splitFiles(diff) {
+		header := block
+		if i := strings.Index(block, "\n@@"); i >= 0 {
+			header = block[:i+1]
+		}
+
+		var f FileChange
+		for _, line := range strings.Split(header, "\n") {
+			switch {
+			case strings.HasPrefix(line, "--- a/"):
+				f.OldPath = strings.TrimPrefix(line, "--- a/")
+			case strings.HasPrefix(line, "+++ b/"):
+				f.NewPath = strings.TrimPrefix(line, "+++ b/")
+			case strings.HasPrefix(line, "rename from "):
+				f.OldPath = strings.TrimPrefix(line, "rename from ")
+			case strings.HasPrefix(line, "rename to "):
+				f.NewPath = strings.TrimPrefix(line, "rename to ")
+			}
+		}
+
+		// Mode-only changes have no ---/+++ lines
+		if f.OldPath == "" && f.NewPath == "" && !isAtomic(header) {
+			f.OldPath = patchPath(header)
+			f.NewPath = f.OldPath
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// BuildPatch joins hunks into a patch, in their original order and with one
+// file header per file
+func BuildPatch(hunks []Hunk) string {
+	sorted := append([]Hunk(nil), hunks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	var b strings.Builder
+	lastHeader := ""
+	for _, h := range sorted {
+		if h.Header != lastHeader {
+			b.WriteString(h.Header)
+			lastHeader = h.Header
+		}
+		b.WriteString(h.Body)
+	}
+	return b.String()
+}
+
+// ApplyCached applies patch to the index only, leaving the working tree alone
+func ApplyCached(patch string) error {
+	cmd := exec.Command("git", "apply", "--cached", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git apply failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// WriteIndexTree stores the current index as a tree object and returns its
+// hash, so that the index can be restored with ReadTree
+func WriteIndexTree() (string, error) {
+	cmd := exec.Command("git", "write-tree")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git write-tree failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// ReadTree replaces the index with the given tree
+func ReadTree(tree string) error {
+	cmd := exec.Command("git", "read-tree", tree)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git read-tree failed: %w", err)
+	}
+
+	return nil
+}
+
+// ResetIndex makes the index match HEAD, or empties it before the first
+// commit, without touching the working tree
+func ResetIndex() error {
+	args := []string{"reset", "--quiet"}
+	if _, err := ResolveCommit("HEAD"); err != nil {
+		args = []string{"read-tree", "--empty"}
+	}
+
+	cmd := exec.Command("git", args...)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+
+	return nil
+}
+
+// ResetSoft moves the current branch to commit, keeping the index and working
+// tree. An empty commit returns the branch to its unborn state.
+func ResetSoft(commit string) error {
+	args := []string{"reset", "--quiet", "--soft", commit}
+	if commit == "" {
+		args = []string{"update-ref", "-d", "HEAD"}
+	}
+
+	cmd := exec.Command("git", args...)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+
+	return nil
+}
+
+func splitFiles(patch string) []string {
+	var blocks []string
+	start := -1
+	for i := 0; i < len(patch); {
+		end := strings.IndexByte(patch[i:], '\n')
+		if end < 0 {
+			end = len(patch)
+		} else {
+			end += i + 1
+		}
+
+		if strings.HasPrefix(patch[i:], "diff --git ") {
+			if start >= 0 {
+				blocks = append(blocks, patch[start:i])
+			}
+			start = i
+		}
+		i = end
+	}
+	if start >= 0 {
+		blocks = append(blocks, patch[start:])
+	}
+	return blocks
+}
+
+func splitHunks(body string) []string {
+	var parts []string
+	start := 0
+	for i := 1; i < len(body); i++ {
+		if body[i-1] == '\n' && strings.HasPrefix(body[i:], "@@") {
+			parts = append(parts, body[start:i])
+			start = i
+		}
+	}
+	return append(parts, body[start:])
+}
+
+// isAtomic reports whether the file header describes a change that has to be
+// applied as a whole
+func isAtomic(header string) bool {
+	for _, marker := range []string{"\nnew file mode", "\ndeleted file mode", "\nrename from", "\ncopy from", "\nGIT binary patch", "\nBinary files"} {
+		if strings.Contains(header, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func patchPath(header string) string {
+	for _, line := range strings.Split(header, "\n") {
+		if path, ok := strings.CutPrefix(line, "+++ b/"); ok {
+			return path
+		}
+	}
+	for _, line := range strings.Split(header, "\n") {
+		if path, ok := strings.CutPrefix(line, "--- a/"); ok {
+			return path
+		}
+	}
+
+	// Binary and mode-only changes have no ---/+++ lines
+	first, _, _ := strings.Cut(header, "\n")
+	if _, path, ok := strings.Cut(first, " b/"); ok {
+		return path
+	}
+	return first
+}
+
+// OldRange returns the first line and the number of lines the hunk covers in
+// the original file, or zeros for whole-file hunks
+func (h Hunk) OldRange() (start, count int) {
+	start, count, _, _ = parseHunkHeader(h.Body)
+	return start, count
+}
+
+// NewRange returns the first line and the number of lines the hunk covers in
+// the changed file, or zeros for whole-file hunks
+func (h Hunk) NewRange() (start, count int) {
+	_, _, start, count = parseHunkHeader(h.Body)
+	return start, count
+}
+
+// parseHunkHeader reads the ranges of an "@@ -a,b +c,d @@" line
+func parseHunkHeader(body string) (oldStart, oldCount, newStart, newCount int) {
+	line, _, _ := strings.Cut(body, "\n")
+	if !strings.HasPrefix(line, "@@ -") {
+		return 0, 0, 0, 0
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return 0, 0, 0, 0
+	}
+	oldStart, oldCount = parseRange(strings.TrimPrefix(fields[1], "-"))
+	newStart, newCount = parseRange(strings.TrimPrefix(fields[2], "+"))
+	return oldStart, oldCount, newStart, newCount
+}
+
+func parseRange(r string) (start, count int) {
+	startStr, countStr, ok := strings.Cut(r, ",")
+	start, _ = strconv.Atoi(startStr)
+	count = 1
+	if ok {
+		count, _ = strconv.Atoi(countStr)
+	}
+	return start, count
+}
+
+// changedOldLines returns the lines of the original file that the hunk
+// removes. For pure additions it returns the context lines around the
+// insertion, since those are what the new lines extend.
+func (h Hunk) changedOldLines() []int {
+	start, _ := h.OldRange()
+	if start == 0 {
+		return nil
+	}
+
+	var removed, around []int
+	line := start
+	lines := strings.Split(h.Body, "\n")
+	for i, l := range lines[1:] {
+		switch {
+		case strings.HasPrefix(l, "-"):
+			removed = append(removed, line)
+			line++
+		case strings.HasPrefix(l, "+"):
+			// The context line before the addition and the one after it
+			if line > start {
+				around = append(around, line-1)
+			}
+			if i+2 < len(lines) && strings.HasPrefix(lines[i+2], " ") {
+				around = append(around, line)
+			}
+		case strings.HasPrefix(l, " "):
+			line++
+		}
+	}
+
+	if len(removed) > 0 {
+		return removed
+	}
+	return around
+}
+
+// ErrRestoreFailed is wrapped by CommitHunks errors when the original state
+// could not be restored after a failure
+var ErrRestoreFailed = errors.New("restoring the original state failed")
+
+// HunkCommit is a commit made of some of the staged hunks
+type HunkCommit struct {
+	Message string
+	Hunks   []Hunk
+}
+
+// CommitHunks creates one commit per entry by staging only its hunks. On
+// failure the branch and the index are restored to their original state.
+func CommitHunks(commits []HunkCommit, opts CommitOptions) (err error) {
+	head, _ := ResolveCommit("HEAD")
+	staged, err := WriteIndexTree()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		if resetErr := ResetSoft(head); resetErr != nil {
+			err = fmt.Errorf("%w; %w: %v", err, ErrRestoreFailed, resetErr)
+			return
+		}
+		if readErr := ReadTree(staged); readErr != nil {
+			err = fmt.Errorf("%w; %w: %v", err, ErrRestoreFailed, readErr)
+		}
+	}()
+
+	if err = ResetIndex(); err != nil {
+		return err
+	}
+
+	for i, c := range commits {
+		if err = ApplyCached(BuildPatch(c.Hunks)); err != nil {
+			return fmt.Errorf("failed to stage commit %d: %w", i+1, err)
+		}
+		if err = Commit(c.Message, opts); err != nil {
+			return fmt.Errorf("failed to create commit %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStagedPatch(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+
+	// Two separate changes in one file plus a new file
+	lines[1], lines[18] = "top change", "bottom change"
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+	createAndAddFile(t, tmpDir, "new.txt", "new\n")
+
+	patch, err := GetStagedPatch()
+	require.NoError(t, err)
+	hunks := ParsePatch(patch)
+	require.Len(t, hunks, 3)
+	require.Equal(t, "file.txt", hunks[0].File)
+	require.Contains(t, hunks[0].Body, "+top change")
+	require.Contains(t, hunks[1].Body, "+bottom change")
+	require.Equal(t, "new.txt", hunks[2].File)
+	require.Contains(t, hunks[2].Summary(), "new.txt @@")
+	require.Equal(t, patch, BuildPatch([]Hunk{hunks[2], hunks[0], hunks[1]}))
+
+	staged, err := WriteIndexTree()
+	require.NoError(t, err)
+
+	// Commit the bottom change first, then the rest
+	require.NoError(t, ResetIndex())
+	require.NoError(t, ApplyCached(BuildPatch([]Hunk{hunks[1]})))
+	require.NoError(t, Commit("bottom", CommitOptions{}))
+	require.NoError(t, ApplyCached(BuildPatch([]Hunk{hunks[0], hunks[2]})))
+	require.NoError(t, Commit("top and new", CommitOptions{}))
+
+	head := gitOutput(t, tmpDir, "rev-parse", "HEAD^{tree}")
+	require.Equal(t, staged, head, "The commits should add up to the staged changes")
+	require.Equal(t, "file.txt", gitOutput(t, tmpDir, "show", "--name-only", "--format=", "HEAD~1"))
+
+	// The working tree is untouched
+	content, err := os.ReadFile(filepath.Join(tmpDir, "new.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "new\n", string(content))
+}
+
+func TestDiffFiles(t *testing.T) {
+	diff := `diff --git a/mod.go b/mod.go
+index 1111111..2222222 100644
+--- a/mod.go
++++ b/mod.go
+@@ -1 +1 @@
+-a
++b
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++c
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 4444444..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-d
+diff --git a/old.go b/moved.go
+similarity index 100%
+rename from old.go
+rename to moved.go
+diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`
+	require.Equal(t, []FileChange{
+		{OldPath: "mod.go", NewPath: "mod.go"},
+		{NewPath: "new.go"},
+		{OldPath: "gone.go"},
+		{OldPath: "old.go", NewPath: "moved.go"},
+		{OldPath: "run.sh", NewPath: "run.sh"},
+	}, DiffFiles(diff))
+}
+
+func TestHunkRanges(t *testing.T) {
+	h := Hunk{Body: "@@ -10,3 +12,4 @@ func main() {\n a()\n"}
+	start, count := h.OldRange()
+	require.Equal(t, []int{10, 3}, []int{start, count})
+	start, count = h.NewRange()
+	require.Equal(t, []int{12, 4}, []int{start, count})
+}
+
+func TestCommitHunksRestoresStateOnFailure(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+
+	lines[0], lines[19] = "first", "last"
+	createAndAddFile(t, tmpDir, "file.txt", strings.Join(lines, "\n")+"\n")
+
+	patch, err := GetStagedPatch()
+	require.NoError(t, err)
+	hunks := ParsePatch(patch)
+	require.Len(t, hunks, 2)
+
+	head, err := ResolveCommit("HEAD")
+	require.NoError(t, err)
+	tree, err := WriteIndexTree()
+	require.NoError(t, err)
+
+	// The hook lets the first commit through and rejects the second
+	hook := "#!/bin/sh\nif [ -f .git/first-done ]; then exit 1; fi\ntouch .git/first-done\n"
+	require.NoError(t, os.WriteFile(filepath.Join(".git", "hooks", "pre-commit"), []byte(hook), 0o755))
+
+	err = CommitHunks([]HunkCommit{
+		{Message: "change first line", Hunks: hunks[:1]},
+		{Message: "change last line", Hunks: hunks[1:]},
+	}, CommitOptions{})
+	require.ErrorContains(t, err, "failed to create commit 2")
+	require.NotErrorIs(t, err, ErrRestoreFailed)
+
+	restoredHead, err := ResolveCommit("HEAD")
+	require.NoError(t, err)
+	require.Equal(t, head, restoredHead, "The first commit is undone")
+	restoredTree, err := WriteIndexTree()
+	require.NoError(t, err)
+	require.Equal(t, tree, restoredTree, "Both hunks are staged again")
+
+	require.NoError(t, os.Remove(filepath.Join(".git", "hooks", "pre-commit")))
+	require.NoError(t, CommitHunks([]HunkCommit{
+		{Message: "change first line", Hunks: hunks[:1]},
+		{Message: "change last line", Hunks: hunks[1:]},
+	}, CommitOptions{}))
+	require.Equal(t, "change last line\nchange first line\ninitial", gitOutput(t, tmpDir, "log", "--format=%s"))
+}
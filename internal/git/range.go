@@ -0,0 +1,212 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is a commit in a range of history
+type LogEntry struct {
+	Commit string
+	Author string
+	// Date is the author date as YYYY-MM-DD
+	Date    string
+	Subject string
+	Body    string
+}
+
+// Message returns the full commit message
+func (e LogEntry) Message() string {
+	if e.Body == "" {
+		return e.Subject
+	}
+	return e.Subject + "\n\n" + e.Body
+}
+
+// DefaultBranch returns the branch pull requests are usually opened against:
+// the remote's HEAD when known, otherwise a local main or master branch
+func DefaultBranch() (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err == nil {
+		return strings.TrimSpace(out.String()), nil
+	}
+
+	for _, name := range []string{"main", "master"} {
+		exists, err := BranchExists(name)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("cannot determine the default branch; pass it with --base")
+}
+
+// MergeBase returns the best common ancestor of a and b
+func MergeBase(a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%s and %s have no common history", a, b)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Log returns the non-merge commits reachable from to but not from from,
+// oldest first. An empty from lists every commit reachable from to.
+func Log(from, to string) ([]LogEntry, error) {
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+	return log("--no-merges", "--reverse", rev)
+}
+
+// ShowCommit returns the commit rev refers to
+func ShowCommit(rev string) (LogEntry, error) {
+	entries, err := log("-1", rev+"^{commit}")
+	if err != nil {
+		return LogEntry{}, err
+	}
+	if len(entries) == 0 {
+		return LogEntry{}, fmt.Errorf("unknown revision %q", rev)
+	}
+	return entries[0], nil
+}
+
+// ReleaseDate returns when rev was released: the date an annotated tag was
+// made, or the committer date of any other revision
+func ReleaseDate(rev string) (time.Time, error) {
+	cmd := exec.Command("git", "cat-file", "-t", rev)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return time.Time{}, fmt.Errorf("unknown revision %q", rev)
+	}
+
+	if strings.TrimSpace(out.String()) == "tag" {
+		cmd = exec.Command("git", "cat-file", "tag", rev)
+		out.Reset()
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return time.Time{}, fmt.Errorf("git cat-file failed: %w", err)
+		}
+		for _, line := range strings.Split(out.String(), "\n") {
+			if line == "" {
+				break
+			}
+			if strings.HasPrefix(line, "tagger ") {
+				return identDate(line)
+			}
+		}
+		// Tags made without a tagger fall back to the commit they point to
+	}
+
+	cmd = exec.Command("git", "log", "-1", "--format=%cI", rev+"^{commit}")
+	out.Reset()
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return time.Time{}, fmt.Errorf("git log failed: %w", err)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(out.String()))
+}
+
+// identDate reads the date at the end of an identity line such as
+// "tagger Name <email> 1700000000 +0100"
+func identDate(line string) (time.Time, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return time.Time{}, fmt.Errorf("invalid identity %q", line)
+	}
+	seconds, err := strconv.ParseInt(fields[len(fields)-2], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid identity %q", line)
+	}
+	zone, err := time.Parse("-0700", fields[len(fields)-1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid identity %q", line)
+	}
+	return time.Unix(seconds, 0).In(zone.Location()), nil
+}
+
+// FileLog returns the last limit commits that changed path, following
+// renames, oldest first
+func FileLog(path string, limit int) ([]LogEntry, error) {
+	entries, err := log("--follow", fmt.Sprintf("--max-count=%d", limit), "HEAD", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// log runs git log with args and parses the commits it lists
+func log(args ...string) ([]LogEntry, error) {
+	args = append([]string{"log", "--date=short", "--format=%H%x00%an%x00%ad%x00%s%x00%b%x1e"}, args...)
+	cmd := exec.Command("git", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var entries []LogEntry
+	for _, record := range strings.Split(out.String(), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		entries = append(entries, LogEntry{
+			Commit:  fields[0],
+			Author:  fields[1],
+			Date:    fields[2],
+			Subject: fields[3],
+			Body:    strings.TrimSpace(fields[4]),
+		})
+	}
+	return entries, nil
+}
+
+// GetRangeDiff returns the combined changes between two revisions
+func GetRangeDiff(from, to string) (string, error) {
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", from, to)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// GetPathDiff returns the changes to path between two revisions, or between
+// from and the working tree when to is empty
+func GetPathDiff(from, to, path string) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	cmd := exec.Command("git", append(args, "--", path)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+
+	return out.String(), nil
+}
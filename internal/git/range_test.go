@@ -0,0 +1,150 @@
+package git
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogAndRangeDiff(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	createAndAddFile(t, tmpDir, "file.txt", "initial\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+	gitOutput(t, tmpDir, "branch", "-M", "main")
+
+	base, err := DefaultBranch()
+	require.NoError(t, err)
+	require.Equal(t, "main", base, "A local main branch is the fallback")
+
+	require.NoError(t, CreateBranch("feat/refunds"))
+	createAndAddFile(t, tmpDir, "refund.txt", "refund\n")
+	require.NoError(t, Commit("feat: add refunds\n\nRefund whole orders.", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "file.txt", "changed\n")
+	require.NoError(t, Commit("fix: update file", CommitOptions{}))
+
+	mergeBase, err := MergeBase("main", "HEAD")
+	require.NoError(t, err)
+	mainCommit, err := ResolveCommit("main")
+	require.NoError(t, err)
+	require.Equal(t, mainCommit, mergeBase)
+
+	entries, err := Log(mergeBase, "HEAD")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "feat: add refunds", entries[0].Subject, "Commits are listed oldest first")
+	require.Equal(t, "feat: add refunds\n\nRefund whole orders.", entries[0].Message())
+	require.Equal(t, "fix: update file", entries[1].Message())
+
+	diff, err := GetRangeDiff(mergeBase, "HEAD")
+	require.NoError(t, err)
+	require.Contains(t, diff, "+refund")
+	require.Contains(t, diff, "+changed")
+
+	_, err = MergeBase("main", "no-such-branch")
+	require.Error(t, err)
+}
+
+func TestShowCommitAndFileLog(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	createAndAddFile(t, tmpDir, "old.txt", "one\n")
+	require.NoError(t, Commit("feat: add file", CommitOptions{}))
+	root, err := ResolveCommit("HEAD")
+	require.NoError(t, err)
+	gitOutput(t, tmpDir, "mv", "old.txt", "file.txt")
+	require.NoError(t, Commit("refactor: rename file", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "other.txt", "other\n")
+	require.NoError(t, Commit("chore: add other file", CommitOptions{}))
+	createAndAddFile(t, tmpDir, "file.txt", "one\ntwo\n")
+	require.NoError(t, Commit("fix: add line\n\nThe second line was missing.", CommitOptions{}))
+
+	entry, err := ShowCommit("HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "fix: add line", entry.Subject)
+	require.Equal(t, "The second line was missing.", entry.Body)
+	require.NotEmpty(t, entry.Author)
+	require.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, entry.Date)
+
+	_, err = ShowCommit("no-such-rev")
+	require.Error(t, err)
+
+	entries, err := FileLog("file.txt", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "Renames are followed and unrelated commits skipped")
+	require.Equal(t, "feat: add file", entries[0].Subject, "Commits are listed oldest first")
+	require.Equal(t, "fix: add line", entries[2].Subject)
+
+	entries, err = FileLog("file.txt", 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "fix: add line", entries[0].Subject)
+
+	base, err := BaseOf(root)
+	require.NoError(t, err)
+	require.Equal(t, emptyTree, base, "Root commits are diffed against the empty tree")
+
+	require.NoError(t, os.WriteFile("file.txt", []byte("one\ntwo\nthree\n"), 0o644))
+	diff, err := GetPathDiff("HEAD^", "", "file.txt")
+	require.NoError(t, err)
+	require.Contains(t, diff, "+two")
+	require.Contains(t, diff, "+three", "Working tree changes are included without to")
+	require.NotContains(t, diff, "other")
+}
+
+func TestReleaseDate(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	t.Setenv("GIT_COMMITTER_DATE", "2024-03-01T10:00:00+01:00")
+	createAndAddFile(t, tmpDir, "file.txt", "initial\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+	gitOutput(t, tmpDir, "tag", "v1.0.0")
+
+	// The tagger date of an annotated tag is taken from the same variable
+	t.Setenv("GIT_COMMITTER_DATE", "2024-03-05T18:30:00+01:00")
+	gitOutput(t, tmpDir, "tag", "-a", "-m", "Release v1.1.0", "v1.1.0")
+
+	date, err := ReleaseDate("v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-01T10:00:00+01:00", date.Format(time.RFC3339), "Lightweight tags use the commit date")
+
+	date, err = ReleaseDate("v1.1.0")
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-05T18:30:00+01:00", date.Format(time.RFC3339), "Annotated tags use the tag date")
+
+	date, err = ReleaseDate("HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-01", date.Format("2006-01-02"))
+
+	_, err = ReleaseDate("no-such-tag")
+	require.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// CommitOptions controls the sign-off and the cryptographic signature of a
+// commit. The two are unrelated: a sign-off is only a trailer certifying the
+// Developer Certificate of Origin, while a signature proves who made the commit.
+type CommitOptions struct {
+	// Signoff adds a Signed-off-by trailer to the message
+	Signoff bool
+	// GPGSign is empty to follow commit.gpgsign, "false" to never sign, "true"
+	// to sign with user.signingkey, or the key to sign with
+	GPGSign string
+}
+
+func (o CommitOptions) args() []string {
+	var args []string
+	if o.Signoff {
+		args = append(args, "--signoff")
+	}
+	return append(args, o.gpgArgs()...)
+}
+
+// gpgArgs returns the signing arguments alone, which git commit-tree and git
+// rebase accept in the same form as git commit
+func (o CommitOptions) gpgArgs() []string {
+	switch o.GPGSign {
+	case "":
+		return nil
+	case "false":
+		return []string{"--no-gpg-sign"}
+	case "true":
+		return []string{"--gpg-sign"}
+	default:
+		return []string{"--gpg-sign=" + o.GPGSign}
+	}
+}
+
+// SigningConfig is the part of git's configuration that decides whether and
+// how commits are signed
+type SigningConfig struct {
+	// Sign is commit.gpgsign
+	Sign bool
+	// Format is gpg.format: openpgp, ssh or x509
+	Format string
+	// Key is user.signingkey
+	Key string
+}
+
+// GetSigningConfig reads the signing settings git applies in the current
+// repository
+func GetSigningConfig() (SigningConfig, error) {
+	cfg := SigningConfig{Format: "openpgp"}
+
+	sign, err := configValue("--type=bool", "commit.gpgsign")
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Sign, _ = strconv.ParseBool(sign)
+
+	if format, err := configValue("gpg.format"); err != nil {
+		return cfg, err
+	} else if format != "" {
+		cfg.Format = format
+	}
+
+	if cfg.Key, err = configValue("user.signingkey"); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// WillSign reports whether a commit made with opts gets signed
+func (c SigningConfig) WillSign(opts CommitOptions) bool {
+	switch opts.GPGSign {
+	case "":
+		return c.Sign
+	case "false":
+		return false
+	default:
+		return true
+	}
+}
+
+// Check reports signing setups that cannot work before git is run, so that
+// the user gets an explanation rather than a failed commit
+func (c SigningConfig) Check(opts CommitOptions) error {
+	if !c.WillSign(opts) {
+		return nil
+	}
+
+	explicitKey := opts.GPGSign != "" && opts.GPGSign != "true"
+	if c.Format == "ssh" && c.Key == "" && !explicitKey {
+		return errors.New("SSH signing needs a key: set it with `git config user.signingkey ~/.ssh/id_ed25519.pub` or pass --gpg-sign=<key>")
+	}
+	return nil
+}
+
+// signingError translates the stderr of a failed commit into an actionable
+// error when signing caused the failure, and returns nil otherwise
+func signingError(stderr string, cfg SigningConfig) error {
+	lower := strings.ToLower(stderr)
+	if !strings.Contains(lower, "failed to sign") && !strings.Contains(lower, "signing failed") &&
+		!strings.Contains(lower, "failed to write commit object") {
+		return nil
+	}
+
+	var hint string
+	switch {
+	case strings.Contains(lower, "no secret key") || strings.Contains(lower, "no default secret key") || strings.Contains(lower, "unusable secret key"):
+		hint = "no usable secret key was found; check user.signingkey against `gpg --list-secret-keys`"
+	case strings.Contains(lower, "inappropriate ioctl") || strings.Contains(lower, "no pinentry") || strings.Contains(lower, "cannot open tty"):
+		hint = "gpg could not ask for the passphrase; run `export GPG_TTY=$(tty)` or unlock the key in gpg-agent first"
+	case strings.Contains(lower, "operation cancelled") || strings.Contains(lower, "timeout"):
+		hint = "the passphrase prompt was cancelled or timed out; unlock the key and try again"
+	case strings.Contains(lower, "agent refused operation") || strings.Contains(lower, "could not open a connection to your authentication agent") ||
+		strings.Contains(lower, "error connecting to agent"):
+		hint = "the SSH agent is locked or not running; start it and add the key with `ssh-add`"
+	case strings.Contains(lower, "incorrect passphrase"):
+		hint = "the key's passphrase was wrong"
+	case strings.Contains(lower, "couldn't load public key") || strings.Contains(lower, "no such file or directory"):
+		hint = fmt.Sprintf("the signing key %q does not exist; point user.signingkey at your public key", cfg.Key)
+	case strings.Contains(lower, "cannot run gpg") || strings.Contains(lower, "cannot run ssh-keygen"):
+		hint = "the signing program is not installed or not on PATH; set gpg.program or gpg.ssh.program"
+	default:
+		hint = "run the commit again with GIT_TRACE=1 to see the signing command git runs"
+	}
+
+	return fmt.Errorf("signing the commit with %s failed: %s", cfg.Format, hint)
+}
+
+// configValue returns the value of a git config key, or an empty string when
+// it is not set
+func configValue(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"config", "--get"}, args...)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git config failed: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitOptionsArgs(t *testing.T) {
+	require.Empty(t, CommitOptions{}.args(), "Without options git's own config decides")
+	require.Equal(t, []string{"--signoff"}, CommitOptions{Signoff: true}.args())
+	require.Equal(t, []string{"--gpg-sign"}, CommitOptions{GPGSign: "true"}.args())
+	require.Equal(t, []string{"--no-gpg-sign"}, CommitOptions{GPGSign: "false"}.args())
+	require.Equal(t, []string{"--signoff", "--gpg-sign=ABCD1234"}, CommitOptions{Signoff: true, GPGSign: "ABCD1234"}.args())
+}
+
+func TestSigningConfigCheck(t *testing.T) {
+	ssh := SigningConfig{Sign: true, Format: "ssh"}
+	require.True(t, ssh.WillSign(CommitOptions{}), "commit.gpgsign applies when no option is given")
+	require.False(t, ssh.WillSign(CommitOptions{GPGSign: "false"}))
+	require.Error(t, ssh.Check(CommitOptions{}), "SSH signing without a key cannot work")
+	require.NoError(t, ssh.Check(CommitOptions{GPGSign: "~/.ssh/id_ed25519.pub"}), "An explicit key is enough")
+	require.NoError(t, ssh.Check(CommitOptions{GPGSign: "false"}))
+
+	openpgp := SigningConfig{Format: "openpgp"}
+	require.False(t, openpgp.WillSign(CommitOptions{Signoff: true}), "A sign-off is not a signature")
+	require.NoError(t, openpgp.Check(CommitOptions{GPGSign: "true"}), "gpg falls back to the committer identity")
+}
+
+func TestSigningError(t *testing.T) {
+	cfg := SigningConfig{Format: "openpgp"}
+
+	err := signingError("error: gpg failed to sign the data\nfatal: failed to write commit object\n", cfg)
+	require.ErrorContains(t, err, "signing the commit with openpgp failed")
+
+	err = signingError("gpg: signing failed: Inappropriate ioctl for device\nerror: gpg failed to sign the data\n", cfg)
+	require.ErrorContains(t, err, "GPG_TTY")
+
+	err = signingError("gpg: skipped \"X\": No secret key\nerror: gpg failed to sign the data\n", cfg)
+	require.ErrorContains(t, err, "gpg --list-secret-keys")
+
+	ssh := SigningConfig{Format: "ssh", Key: "/missing.pub"}
+	err = signingError("error: Couldn't load public key /missing.pub: No such file or directory?\n\nfatal: failed to write commit object\n", ssh)
+	require.ErrorContains(t, err, `"/missing.pub" does not exist`)
+
+	err = signingError("sign_and_send_pubkey: signing failed for ED25519: agent refused operation\n", ssh)
+	require.ErrorContains(t, err, "ssh-add")
+
+	require.NoError(t, signingError("error: pathspec 'x' did not match any file(s) known to git\n", cfg), "Other failures are left alone")
+}
+
+func TestCommitReportsSigningFailure(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("Skipping as ssh-keygen is not installed")
+	}
+
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	gitOutput(t, tmpDir, "config", "gpg.format", "ssh")
+	createAndAddFile(t, tmpDir, "file.txt", "content\n")
+
+	err = Commit("signed", CommitOptions{GPGSign: "true"})
+	require.ErrorContains(t, err, "user.signingkey", "A missing SSH key is reported before running git")
+
+	gitOutput(t, tmpDir, "config", "user.signingkey", tmpDir+"/missing.pub")
+	cfg, err := GetSigningConfig()
+	require.NoError(t, err)
+	require.Equal(t, SigningConfig{Format: "ssh", Key: tmpDir + "/missing.pub"}, cfg)
+
+	err = Commit("signed", CommitOptions{GPGSign: "true"})
+	require.ErrorContains(t, err, "signing the commit with ssh failed")
+
+	// Without a signature the same commit succeeds
+	require.NoError(t, Commit("unsigned", CommitOptions{Signoff: true}))
+	require.Contains(t, gitOutput(t, tmpDir, "log", "-1", "--format=%B"), "Signed-off-by: Test User <test@example.com>")
+}
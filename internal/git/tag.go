@@ -0,0 +1,36 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// MergedTags returns the tags that point at rev or one of its ancestors
+func MergedTags(rev string) ([]string, error) {
+	cmd := exec.Command("git", "tag", "--list", "--merged", rev)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git tag failed: %w", err)
+	}
+
+	return strings.Fields(out.String()), nil
+}
+
+// CreateTag creates an annotated tag at HEAD. The message is kept verbatim,
+// so markdown headings are not taken for comments.
+func CreateTag(name, message string) error {
+	cmd := exec.Command("git", "tag", "--annotate", "--cleanup=verbatim", "--file=-", name)
+	cmd.Stdin = strings.NewReader(message)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("git tag failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
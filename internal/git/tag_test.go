@@ -0,0 +1,42 @@
+package git
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	// Setup
+	tmpDir := setupGitRepo(t)
+	defer os.RemoveAll(tmpDir)
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	defer os.Chdir(currentDir)
+
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err, "Failed to change directory")
+
+	createAndAddFile(t, tmpDir, "file.txt", "initial\n")
+	require.NoError(t, Commit("initial", CommitOptions{}))
+	require.NoError(t, CreateTag("v0.1.0", "## v0.1.0\n\n- initial\n"))
+
+	require.NoError(t, CreateBranch("other"))
+	createAndAddFile(t, tmpDir, "other.txt", "other\n")
+	require.NoError(t, Commit("feat: other", CommitOptions{}))
+	require.NoError(t, CreateTag("v0.2.0", "other"))
+	gitOutput(t, tmpDir, "checkout", "--quiet", "-")
+
+	tags, err := MergedTags("HEAD")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v0.1.0"}, tags, "Tags on other branches are not merged")
+
+	require.Equal(t, "## v0.1.0\n\n- initial", gitOutput(t, tmpDir, "tag", "--list", "--format=%(contents)", "v0.1.0"), "Headings are kept")
+	require.Error(t, CreateTag("v0.1.0", "again"))
+
+	entries, err := Log("", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "An empty start lists every commit")
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Trailer is a "Key: value" line at the end of a commit message, such as
+// Co-authored-by or Refs
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// ParseTrailer reads a trailer written as key=value or "Key: value"
+func ParseTrailer(s string) (Trailer, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if colon := strings.Index(s, ":"); colon >= 0 && (!ok || colon < len(key)) {
+		key, value, ok = s[:colon], s[colon+1:], true
+	}
+
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" || value == "" {
+		return Trailer{}, fmt.Errorf("invalid trailer %q: expected key=value", s)
+	}
+	if strings.ContainsAny(key, " \t") {
+		return Trailer{}, fmt.Errorf("invalid trailer key %q: must not contain spaces", key)
+	}
+	return Trailer{Key: key, Value: value}, nil
+}
+
+// AddTrailers appends trailers to message with git interpret-trailers, which
+// places them in the message's trailer block and skips exact duplicates
+func AddTrailers(message string, trailers []Trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, t := range trailers {
+		args = append(args, "--trailer", t.String())
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(strings.TrimRight(message, "\n") + "\n")
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git interpret-trailers failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(out.String(), "\n"), nil
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrailer(t *testing.T) {
+	trailer, err := ParseTrailer("Refs=PAY-1234")
+	require.NoError(t, err)
+	require.Equal(t, Trailer{Key: "Refs", Value: "PAY-1234"}, trailer)
+
+	trailer, err = ParseTrailer("Reviewed-by: Jane Doe <jane@example.com>")
+	require.NoError(t, err)
+	require.Equal(t, "Reviewed-by: Jane Doe <jane@example.com>", trailer.String())
+
+	trailer, err = ParseTrailer("Link=https://example.com/a?b=c")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/a?b=c", trailer.Value, "Only the first separator splits")
+
+	for _, invalid := range []string{"Refs", "Refs=", "=PAY-1", "Reviewed by=Jane"} {
+		_, err = ParseTrailer(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestAddTrailers(t *testing.T) {
+	message, err := AddTrailers("feat: add refunds", []Trailer{{Key: "Refs", Value: "PAY-1234"}})
+	require.NoError(t, err)
+	require.Equal(t, "feat: add refunds\n\nRefs: PAY-1234", message)
+
+	// Trailers join an existing trailer block and exact duplicates are skipped
+	message, err = AddTrailers("feat: add refunds\n\nRefund whole orders.\n\nRefs: PAY-1234\n", []Trailer{
+		{Key: "Refs", Value: "PAY-1234"},
+		{Key: "Co-authored-by", Value: "Bob Roe <bob@example.com>"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "feat: add refunds\n\nRefund whole orders.\n\nRefs: PAY-1234\nCo-authored-by: Bob Roe <bob@example.com>", message)
+
+	message, err = AddTrailers("fix: a", nil)
+	require.NoError(t, err)
+	require.Equal(t, "fix: a", message)
+}
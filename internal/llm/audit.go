@@ -0,0 +1,241 @@
+package llm
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	auditFileName = "audit.jsonl"
+
+	// DefaultAuditMaxSize is the size in bytes at which the active audit file is rotated
+	DefaultAuditMaxSize = 10 << 20
+	// DefaultAuditMaxFiles is the number of rotated audit files kept on disk
+	DefaultAuditMaxFiles = 5
+)
+
+// AuditEntry is a single exchange with a provider as recorded in the audit log
+type AuditEntry struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Repo      string    `json:"repo,omitempty"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Prompt    string    `json:"prompt"`
+	Response  string    `json:"response"`
+	Usage     Usage     `json:"usage"`
+	LatencyMS int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// AuditLog stores audit entries as JSON lines in a directory, rotating the
+// active file once it grows past MaxSize.
+type AuditLog struct {
+	Dir      string
+	MaxSize  int64
+	MaxFiles int
+
+	mu sync.Mutex
+}
+
+func NewAuditLog(dir string) *AuditLog {
+	return &AuditLog{
+		Dir:      dir,
+		MaxSize:  DefaultAuditMaxSize,
+		MaxFiles: DefaultAuditMaxFiles,
+	}
+}
+
+// Record appends an entry to the audit log
+func (a *AuditLog) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = auditID(entry)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err = os.MkdirAll(a.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	active := filepath.Join(a.Dir, auditFileName)
+	if err = rotateIfFull(active, int64(len(line)), a.MaxSize, a.MaxFiles, entry.Timestamp); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(active, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns every entry in the audit log, oldest first
+func (a *AuditLog) Entries() ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	files, err := a.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	for _, file := range files {
+		fileEntries, err := readAuditFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// Find returns the entry whose ID starts with the given prefix
+func (a *AuditLog) Find(id string) (*AuditEntry, error) {
+	entries, err := a.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *AuditEntry
+	for i := range entries {
+		if !strings.HasPrefix(entries[i].ID, id) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("audit entry id %q is ambiguous", id)
+		}
+		found = &entries[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("audit entry %q not found", id)
+	}
+	return found, nil
+}
+
+// Prune removes every entry recorded before the given time and returns the
+// number of entries removed.
+func (a *AuditLog) Prune(before time.Time) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	files, err := a.files()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		entries, err := readAuditFile(file)
+		if err != nil {
+			return removed, err
+		}
+
+		var kept []AuditEntry
+		for _, entry := range entries {
+			if entry.Timestamp.Before(before) {
+				removed++
+				continue
+			}
+			kept = append(kept, entry)
+		}
+
+		if len(kept) == len(entries) {
+			continue
+		}
+		if len(kept) == 0 {
+			if err = os.Remove(file); err != nil {
+				return removed, fmt.Errorf("failed to remove audit file: %w", err)
+			}
+			continue
+		}
+		if err = writeAuditFile(file, kept); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// files returns the audit files in the log directory, oldest first
+func (a *AuditLog) files() ([]string, error) {
+	return logFiles(filepath.Join(a.Dir, auditFileName))
+}
+
+func readAuditFile(path string) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("corrupt audit entry in %s: %w", path, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+
+	return entries, nil
+}
+
+func writeAuditFile(path string, entries []AuditEntry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit entry: %w", err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace audit file: %w", err)
+	}
+
+	return nil
+}
+
+func auditID(entry AuditEntry) string {
+	sum := sha256.Sum256([]byte(entry.Timestamp.Format(time.RFC3339Nano) + entry.Provider + entry.Model + entry.Prompt))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRecordAndFind(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-audit-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	auditLog := NewAuditLog(tmpDir)
+	err = auditLog.Record(AuditEntry{
+		Timestamp: time.Now(),
+		Provider:  "ollama",
+		Model:     "mistral",
+		Prompt:    "prompt",
+		Response:  "response",
+		Usage:     Usage{PromptTokens: 10, CompletionTokens: 5},
+	})
+	require.NoError(t, err, "Failed to record entry")
+
+	entries, err := auditLog.Entries()
+	require.NoError(t, err, "Failed to read entries")
+	require.Len(t, entries, 1)
+	require.NotEmpty(t, entries[0].ID, "Expected an entry ID to be assigned")
+	require.Equal(t, 10, entries[0].Usage.PromptTokens)
+
+	found, err := auditLog.Find(entries[0].ID[:6])
+	require.NoError(t, err, "Failed to find entry by ID prefix")
+	require.Equal(t, "response", found.Response)
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-audit-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	auditLog := NewAuditLog(tmpDir)
+	auditLog.MaxSize = 300
+	auditLog.MaxFiles = 2
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		err = auditLog.Record(AuditEntry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Provider:  "openrouter",
+			Model:     "model",
+			Prompt:    "a prompt long enough to force rotation",
+		})
+		require.NoError(t, err, "Failed to record entry")
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(tmpDir, "audit-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 2, "Expected old rotated files to be removed")
+
+	entries, err := auditLog.Entries()
+	require.NoError(t, err, "Failed to read entries")
+	require.Less(t, len(entries), 10, "Expected oldest entries to be rotated out")
+	require.Equal(t, start.Add(9*time.Second).Unix(), entries[len(entries)-1].Timestamp.Unix(), "Expected newest entry last")
+}
+
+func TestAuditLogPrune(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-audit-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	auditLog := NewAuditLog(tmpDir)
+	now := time.Now()
+	for _, ts := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Hour), now} {
+		err = auditLog.Record(AuditEntry{Timestamp: ts, Provider: "ollama", Model: "mistral"})
+		require.NoError(t, err, "Failed to record entry")
+	}
+
+	removed, err := auditLog.Prune(now.Add(-24 * time.Hour))
+	require.NoError(t, err, "Failed to prune")
+	require.Equal(t, 1, removed)
+
+	entries, err := auditLog.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
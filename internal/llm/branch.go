@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BranchName is a suggested branch, split into the parts a branch pattern
+// can refer to
+type BranchName struct {
+	Type string `json:"type"`
+	Slug string `json:"slug"`
+}
+
+type branchResponse struct {
+	Branches []BranchName `json:"branches"`
+}
+
+// maxSlugLength keeps branch names short enough to type and read
+const maxSlugLength = 40
+
+// SuggestBranchNames asks the provider for three branch names for the work
+// described by a diff or, when fromDiff is false, by a free-text description
+func SuggestBranchNames(p Provider, input string, fromDiff bool, hints []string) ([]BranchName, *Response, error) {
+	res, err := p.Complete(buildBranchPrompt(input, fromDiff, hints))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, err := parseBranchResponse(res.Content)
+	if err != nil {
+		return nil, res, err
+	}
+	return names, res, nil
+}
+
+func buildBranchPrompt(input string, fromDiff bool, hints []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are a Git branch name generator. Suggest exactly 3 names for a branch holding the work below. Respond with JSON in the following format:
+
+{
+  "branches": [
+    {
+      "type": "feat",
+      "slug": "short-kebab-case-summary"
+    }
+  ]
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+2. Include exactly 3 branches
+3. "type" is one of: feat, fix, docs, style, refactor, test, chore
+4. "slug" is 2 to 5 lowercase words joined by hyphens, summarizing the work
+5. Do NOT include the input in your response
+6. Do NOT include any commentary or markdown
+
+`)
+
+	if fromDiff {
+		fmt.Fprintf(&prompt, "Work in progress:\n```diff\n%s\n```\n\n", strings.TrimRight(input, "\n"))
+	} else {
+		fmt.Fprintf(&prompt, "Description of the planned work:\n%s\n\n", strings.TrimSpace(input))
+	}
+
+	writeHints(&prompt, hints)
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+func parseBranchResponse(content string) ([]BranchName, error) {
+	var response branchResponse
+	if err := json.Unmarshal([]byte(extractJSON(content)), &response); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	var names []BranchName
+	for _, b := range response.Branches {
+		name := BranchName{Type: slugify(b.Type), Slug: slugify(b.Slug)}
+		if name.Slug != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("response contains no branch names")
+	}
+	return names, nil
+}
+
+// Format fills in the {type}, {ticket} and {slug} placeholders of pattern.
+// Separators left dangling by an empty placeholder, such as the hyphen after
+// a missing ticket, are removed.
+func (b BranchName) Format(pattern, ticket string) string {
+	name := strings.NewReplacer("{type}", b.Type, "{ticket}", ticket, "{slug}", b.Slug).Replace(pattern)
+
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		segment = repeatedSeparator.ReplaceAllString(segment, "$1")
+		segment = strings.Trim(segment, "-_.")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var repeatedSeparator = regexp.MustCompile(`([-_.])[-_.]+`)
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases s and joins its words with hyphens, cutting it at a word
+// boundary when it is too long for a branch name
+func slugify(s string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) <= maxSlugLength {
+		return slug
+	}
+
+	slug = slug[:maxSlugLength]
+	if i := strings.LastIndex(slug, "-"); i > 0 {
+		slug = slug[:i]
+	}
+	return strings.Trim(slug, "-")
+}
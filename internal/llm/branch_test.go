@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBranchResponse(t *testing.T) {
+	content := "```json\n" + `{"branches": [
+		{"type": "feat", "slug": "Refund Flow"},
+		{"type": "fix", "slug": "handle partial refunds for orders that were already shipped abroad"},
+		{"type": "chore", "slug": "!!"}
+	]}` + "\n```"
+
+	names, err := parseBranchResponse(content)
+	require.NoError(t, err)
+	require.Equal(t, []BranchName{
+		{Type: "feat", Slug: "refund-flow"},
+		{Type: "fix", Slug: "handle-partial-refunds-for-orders-that"},
+	}, names, "Slugs are normalized, cut at a word and empty ones dropped")
+
+	_, err = parseBranchResponse(`{"branches": []}`)
+	require.Error(t, err)
+}
+
+func TestBranchNameFormat(t *testing.T) {
+	b := BranchName{Type: "feat", Slug: "refund-flow"}
+	require.Equal(t, "feat/refund-flow", b.Format("{type}/{slug}", ""))
+	require.Equal(t, "feat/PAY-1234-refund-flow", b.Format("{type}/{ticket}-{slug}", "PAY-1234"))
+	require.Equal(t, "feat/refund-flow", b.Format("{type}/{ticket}-{slug}", ""), "Separators of an empty ticket are dropped")
+	require.Equal(t, "refund-flow", b.Format("{ticket}/{slug}", ""))
+}
+
+func TestBuildBranchPrompt(t *testing.T) {
+	prompt := buildBranchPrompt("add refunds", false, []string{"The changes belong to ticket PAY-1"})
+	require.Contains(t, prompt, "Description of the planned work:\nadd refunds\n")
+	require.Contains(t, prompt, "- The changes belong to ticket PAY-1")
+
+	prompt = buildBranchPrompt("+a\n", true, nil)
+	require.Contains(t, prompt, "Work in progress:\n```diff\n+a\n```")
+	require.NotContains(t, prompt, "CONTEXT:")
+}
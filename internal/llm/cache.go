@@ -0,0 +1,179 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long cached suggestions stay valid
+	DefaultCacheTTL = 24 * time.Hour
+	// DefaultCacheMaxSize is the total size in bytes the cache may grow to
+	// before the oldest entries are evicted
+	DefaultCacheMaxSize = 5 << 20
+)
+
+// CacheEntry holds the suggestions generated for a single prompt
+type CacheEntry struct {
+	Key         string    `json:"key"`
+	CreatedAt   time.Time `json:"created_at"`
+	Provider    string    `json:"provider"`
+	Model       string    `json:"model"`
+	Suggestions []string  `json:"suggestions"`
+}
+
+// Cache stores generated suggestions on disk, one file per entry, so that
+// asking again for the same diff does not pay for the same generation.
+type Cache struct {
+	Dir     string
+	TTL     time.Duration
+	MaxSize int64
+
+	mu sync.Mutex
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{
+		Dir:     dir,
+		TTL:     DefaultCacheTTL,
+		MaxSize: DefaultCacheMaxSize,
+	}
+}
+
+// CacheKey identifies a suggestion request. It covers the provider, the model
+// and the full prompt, so a change to the diff, the prompt template, the hints
+// or the style and body options produces a different key.
+func CacheKey(provider, model, diff string, includeBody bool, style string, hints []string) string {
+	h := sha256.New()
+	for _, part := range []string{strings.ToLower(provider), model, buildPrompt(diff, includeBody, style, hints)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the entry stored under key if it exists and has not expired
+func (c *Cache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil || c.expired(entry.CreatedAt) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Put stores suggestions under key, evicting expired entries and then the
+// oldest ones until the cache fits in MaxSize.
+func (c *Cache) Put(key, provider, model string, suggestions []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(CacheEntry{
+		Key:         key,
+		CreatedAt:   time.Now().UTC(),
+		Provider:    provider,
+		Model:       model,
+		Suggestions: suggestions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err = os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so a concurrent Get never sees a partial entry
+	tmp := c.path(key) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err = os.Rename(tmp, c.path(key)); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return c.evict()
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *Cache) files() ([]cacheFile, error) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	files := make([]cacheFile, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat cache entry: %w", err)
+		}
+		files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+	}
+
+	// Oldest first
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	return files, nil
+}
+
+func (c *Cache) evict() error {
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	kept := files[:0]
+	for _, f := range files {
+		if c.expired(f.modTime) {
+			os.Remove(f.path)
+			continue
+		}
+		total += f.size
+		kept = append(kept, f)
+	}
+
+	for i := 0; c.MaxSize > 0 && total > c.MaxSize && i < len(kept); i++ {
+		if err = os.Remove(kept[i].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to evict cache entry: %w", err)
+		}
+		total -= kept[i].size
+	}
+
+	return nil
+}
+
+func (c *Cache) expired(created time.Time) bool {
+	return c.TTL > 0 && time.Since(created) > c.TTL
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKey(t *testing.T) {
+	key := CacheKey("ollama", "mistral", "diff", false, "conventional", nil)
+	require.Equal(t, key, CacheKey("Ollama", "mistral", "diff", false, "conventional", nil))
+
+	require.NotEqual(t, key, CacheKey("openrouter", "mistral", "diff", false, "conventional", nil))
+	require.NotEqual(t, key, CacheKey("ollama", "llama3", "diff", false, "conventional", nil))
+	require.NotEqual(t, key, CacheKey("ollama", "mistral", "other diff", false, "conventional", nil))
+	require.NotEqual(t, key, CacheKey("ollama", "mistral", "diff", true, "conventional", nil))
+	require.NotEqual(t, key, CacheKey("ollama", "mistral", "diff", false, "simple", nil))
+	require.NotEqual(t, key, CacheKey("ollama", "mistral", "diff", false, "conventional", []string{"ticket PAY-1"}))
+}
+
+func TestCachePutAndGet(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-cache-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	cache := NewCache(tmpDir)
+	_, ok := cache.Get("missing")
+	require.False(t, ok)
+
+	suggestions := []string{"feat: one", "fix: two", "chore: three"}
+	require.NoError(t, cache.Put("key", "ollama", "mistral", suggestions))
+
+	entry, ok := cache.Get("key")
+	require.True(t, ok)
+	require.Equal(t, suggestions, entry.Suggestions)
+	require.Equal(t, "mistral", entry.Model)
+}
+
+func TestCacheExpiry(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-cache-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	cache := NewCache(tmpDir)
+	require.NoError(t, cache.Put("key", "ollama", "mistral", []string{"a", "b", "c"}))
+
+	cache.TTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	_, ok := cache.Get("key")
+	require.False(t, ok, "Expired entries should not be returned")
+
+	_, err = os.Stat(filepath.Join(tmpDir, "key.json"))
+	require.ErrorIs(t, err, os.ErrNotExist, "Expired entries should be removed")
+}
+
+func TestCacheEvictsOldestOverMaxSize(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-cache-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	cache := NewCache(tmpDir)
+	require.NoError(t, cache.Put("first", "ollama", "mistral", []string{"a", "b", "c"}))
+	info, err := os.Stat(filepath.Join(tmpDir, "first.json"))
+	require.NoError(t, err)
+
+	// Room for two entries
+	cache.MaxSize = 2*info.Size() + 10
+
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(tmpDir, "first.json"), past, past))
+	require.NoError(t, cache.Put("second", "ollama", "mistral", []string{"a", "b", "c"}))
+	require.NoError(t, cache.Put("third", "ollama", "mistral", []string{"a", "b", "c"}))
+
+	_, ok := cache.Get("first")
+	require.False(t, ok, "The oldest entry should have been evicted")
+	_, ok = cache.Get("second")
+	require.True(t, ok)
+	_, ok = cache.Get("third")
+	require.True(t, ok)
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChangeNote is a changelog entry offered to the model for polishing
+type ChangeNote struct {
+	ID   string
+	Type string
+	Text string
+}
+
+// PolishedChangelog is the model's rewrite of a changelog
+type PolishedChangelog struct {
+	Highlights []string
+	// Texts maps the IDs of the notes the model rewrote to their new text
+	Texts map[string]string
+}
+
+type changelogResponse struct {
+	Highlights []string `json:"highlights"`
+	Entries    []struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	} `json:"entries"`
+}
+
+// PolishChangelog asks the provider to rewrite the changelog notes as clear
+// release-note prose and to write a few highlights. Notes the model leaves out
+// or invents are ignored, so the list of changes stays complete.
+func PolishChangelog(p Provider, notes []ChangeNote) (*PolishedChangelog, *Response, error) {
+	res, err := p.Complete(buildChangelogPrompt(notes))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	polished, err := parseChangelogResponse(res.Content, notes)
+	if err != nil {
+		return nil, res, err
+	}
+	return polished, res, nil
+}
+
+func buildChangelogPrompt(notes []ChangeNote) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are a release notes editor. Rewrite each changelog entry below as a clear, user-facing sentence and write 1 to 3 highlights summarizing the most important changes. Respond with JSON in the following format:
+
+{
+  "highlights": ["highlight"],
+  "entries": [
+    {
+      "id": "entry id",
+      "text": "rewritten entry"
+    }
+  ]
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+2. Include every entry exactly once, with its id unchanged
+3. Keep each entry to a single line, without a trailing period
+4. Do not invent changes that the entries do not mention
+5. Do NOT include any commentary or markdown
+
+Entries:
+`)
+
+	for _, n := range notes {
+		fmt.Fprintf(&prompt, "- id: %s, type: %s, text: %s\n", n.ID, n.Type, n.Text)
+	}
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+func parseChangelogResponse(content string, notes []ChangeNote) (*PolishedChangelog, error) {
+	var response changelogResponse
+	if err := json.Unmarshal([]byte(extractJSON(content)), &response); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, n := range notes {
+		known[n.ID] = true
+	}
+
+	polished := &PolishedChangelog{Texts: map[string]string{}}
+	for _, h := range response.Highlights {
+		if h = strings.TrimSpace(h); h != "" {
+			polished.Highlights = append(polished.Highlights, h)
+		}
+	}
+	for _, e := range response.Entries {
+		text := strings.TrimSpace(strings.ReplaceAll(e.Text, "\n", " "))
+		if known[e.ID] && text != "" {
+			polished.Texts[e.ID] = text
+		}
+	}
+	return polished, nil
+}
@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangelogResponse(t *testing.T) {
+	notes := []ChangeNote{
+		{ID: "aaa", Type: "feat", Text: "add dark mode"},
+		{ID: "bbb", Type: "fix", Text: "handle empty diff"},
+	}
+	content := "```json\n" + `{
+  "highlights": ["Dark mode arrives", " "],
+  "entries": [
+    {"id": "aaa", "text": "Added a dark\nmode"},
+    {"id": "zzz", "text": "Invented change"},
+    {"id": "bbb", "text": ""}
+  ]
+}` + "\n```"
+
+	polished, err := parseChangelogResponse(content, notes)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Dark mode arrives"}, polished.Highlights)
+	require.Equal(t, map[string]string{"aaa": "Added a dark mode"}, polished.Texts, "Unknown and empty entries are ignored")
+
+	_, err = parseChangelogResponse("not json", notes)
+	require.Error(t, err)
+}
+
+func TestBuildChangelogPrompt(t *testing.T) {
+	prompt := buildChangelogPrompt([]ChangeNote{{ID: "aaa", Type: "feat", Text: "add dark mode"}})
+	require.Contains(t, prompt, "- id: aaa, type: feat, text: add dark mode\n")
+}
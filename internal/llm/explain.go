@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Explanation formats
+const (
+	ExplainText     = "text"
+	ExplainMarkdown = "markdown"
+)
+
+// ExplainFormats are the formats ExplainChanges can answer in
+var ExplainFormats = []string{ExplainText, ExplainMarkdown}
+
+// ExplainChanges asks the provider to explain diff in plain language and
+// streams the answer to w. subject describes what the diff covers, such as a
+// commit or a range, and commits are the messages of the commits it contains,
+// oldest first, which hint at why the changes were made.
+func ExplainChanges(p Provider, w io.Writer, subject string, commits []string, diff, format string, hints []string) (*Response, error) {
+	if format != ExplainText && format != ExplainMarkdown {
+		return nil, fmt.Errorf("unknown format %q: must be one of %s", format, strings.Join(ExplainFormats, ", "))
+	}
+	return p.Stream(buildExplainPrompt(subject, commits, diff, format, hints), w)
+}
+
+func buildExplainPrompt(subject string, commits []string, diff, format string, hints []string) string {
+	var prompt strings.Builder
+
+	fmt.Fprintf(&prompt, `You are explaining a change in a git repository to a developer who has not seen it. The change is %s.
+
+REQUIREMENTS:
+1. Start with one or two sentences that say what changed and what it means for users of the code
+2. Then walk through the important changes, grouped by purpose rather than by file
+3. Explain the likely reason for each change, using the commit messages as hints; say when a reason is a guess
+4. Point out behavior changes, risks and anything a reviewer should check
+5. Use plain language and skip changes that are only formatting or renames
+6. Do NOT repeat the diff or quote more than a line of code at a time
+`, subject)
+	if format == ExplainMarkdown {
+		prompt.WriteString("7. Answer in markdown with the sections \"## Summary\", \"## Changes\", \"## Why\" and \"## Risks\", using bullet lists and inline code for identifiers\n")
+	} else {
+		prompt.WriteString("7. Answer in plain text without markdown: short paragraphs, with \"- \" for lists, wrapped at 80 columns\n")
+	}
+
+	if len(commits) > 0 {
+		prompt.WriteString("\nCommit messages, oldest first:\n")
+		for i, c := range commits {
+			fmt.Fprintf(&prompt, "%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(c), "\n", "\n   "))
+		}
+	}
+
+	fmt.Fprintf(&prompt, "\nDiff:\n```diff\n%s\n```\n\n", strings.TrimRight(diff, "\n"))
+
+	writeHints(&prompt, hints)
+
+	prompt.WriteString("\nRespond ONLY with the explanation.")
+
+	return prompt.String()
+}
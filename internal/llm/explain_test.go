@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const explainDiff = `diff --git a/refund.go b/refund.go
+--- a/refund.go
++++ b/refund.go
+@@ -1,2 +1,3 @@
+ package shop
++func Refund(id string) error { return nil }
+`
+
+func TestBuildExplainPrompt(t *testing.T) {
+	prompt := buildExplainPrompt("commit abc1234", []string{"feat: add refunds\n\nRefund whole orders."}, explainDiff, ExplainMarkdown, []string{"Ticket: SHOP-12"})
+
+	require.Contains(t, prompt, "The change is commit abc1234.")
+	require.Contains(t, prompt, "1. feat: add refunds\n   \n   Refund whole orders.")
+	require.Contains(t, prompt, "+func Refund(id string) error { return nil }")
+	require.Contains(t, prompt, "## Summary")
+	require.Contains(t, prompt, "CONTEXT:\n- Ticket: SHOP-12")
+	require.NotContains(t, prompt, "JSON", "Explanations are plain text")
+
+	prompt = buildExplainPrompt("the file refund.go", nil, explainDiff, ExplainText, nil)
+	require.Contains(t, prompt, "without markdown")
+	require.NotContains(t, prompt, "Commit messages")
+}
+
+func TestExplainChangesStreamsOllama(t *testing.T) {
+	var req OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/generate", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		for _, piece := range []string{"Adds ", "refunds."} {
+			fmt.Fprintf(w, "{\"response\": %q, \"done\": false}\n", piece)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintln(w, `{"response": "", "done": true, "prompt_eval_count": 120, "eval_count": 4}`)
+	}))
+	defer server.Close()
+
+	var out strings.Builder
+	p := NewOllamaProvider("mistral", WithBaseURL(server.URL))
+	res, err := ExplainChanges(p, &out, "commit abc1234", nil, explainDiff, ExplainText, nil)
+	require.NoError(t, err)
+
+	require.True(t, req.Stream)
+	require.Empty(t, req.Format, "Explanations are not JSON")
+	require.Equal(t, "Adds refunds.", out.String())
+	require.Equal(t, "Adds refunds.", res.Content)
+	require.Equal(t, Usage{PromptTokens: 120, CompletionTokens: 4}, res.Usage)
+}
+
+func TestExplainChangesStreamsOpenRouter(t *testing.T) {
+	var req openRouterStreamRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/chat/completions", r.URL.Path)
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": OPENROUTER PROCESSING\n\n")
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"## Summary\\n\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Adds refunds.\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\": [], \"usage\": {\"prompt_tokens\": 1000, \"completion_tokens\": 100}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	var out strings.Builder
+	p := NewOpenRouterProvider("key", "openai/gpt-4o", WithBaseURL(server.URL))
+	res, err := ExplainChanges(p, &out, "commits main..HEAD", []string{"feat: add refunds"}, explainDiff, ExplainMarkdown, nil)
+	require.NoError(t, err)
+
+	require.True(t, req.Stream)
+	require.True(t, req.Usage.Include)
+	require.Equal(t, "## Summary\nAdds refunds.", out.String())
+	require.Equal(t, Usage{PromptTokens: 1000, CompletionTokens: 100}, res.Usage)
+	require.True(t, res.CostKnown)
+	require.InDelta(t, 0.0035, res.Cost, 1e-9)
+}
+
+func TestExplainChangesReportsStreamErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Adds\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"error\": {\"message\": \"rate limited\"}}\n\n")
+	}))
+	defer server.Close()
+
+	var out strings.Builder
+	p := NewOpenRouterProvider("key", "openai/gpt-4o", WithBaseURL(server.URL))
+	_, err := ExplainChanges(p, &out, "commit abc1234", nil, explainDiff, ExplainText, nil)
+	require.ErrorContains(t, err, "rate limited")
+	require.Equal(t, "Adds", out.String(), "Text streamed before the error is kept")
+
+	_, err = ExplainChanges(p, &out, "commit abc1234", nil, explainDiff, "html", nil)
+	require.ErrorContains(t, err, "unknown format")
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FixupCommit is an earlier commit offered to the model as a fixup target
+type FixupCommit struct {
+	Subject string
+	Diff    string
+}
+
+type fixupResponse struct {
+	Commit int    `json:"commit"`
+	Reason string `json:"reason"`
+}
+
+// ConfirmFixup asks the provider which of the commits the staged diff fixes.
+// It returns the index of the chosen commit, or -1 when the model thinks the
+// diff belongs to none of them, along with the model's reason.
+func ConfirmFixup(p Provider, diff string, commits []FixupCommit) (int, string, *Response, error) {
+	res, err := p.Complete(buildFixupPrompt(diff, commits))
+	if err != nil {
+		return -1, "", nil, err
+	}
+
+	choice, reason, err := parseFixupResponse(res.Content, len(commits))
+	if err != nil {
+		return -1, "", res, err
+	}
+	return choice, reason, res, nil
+}
+
+func buildFixupPrompt(diff string, commits []FixupCommit) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are reviewing a Git branch. The staged changes below were made after the numbered commits and may correct or complete one of them. Decide which commit the staged changes belong to, so they can be folded into it. Respond with JSON in the following format:
+
+{
+  "commit": 1,
+  "reason": "one sentence explaining the choice"
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+2. "commit" is the number of the matching commit, or 0 if the staged changes are unrelated to all of them
+3. Only pick a commit when the staged changes fix, finish or adjust what it introduced
+4. Do NOT include the diff in your response
+5. Do NOT include any commentary or markdown
+
+`)
+
+	fmt.Fprintf(&prompt, "Staged changes:\n```diff\n%s\n```\n\n", strings.TrimRight(diff, "\n"))
+
+	for i, c := range commits {
+		fmt.Fprintf(&prompt, "Commit %d: %s\n```diff\n%s\n```\n\n", i+1, c.Subject, strings.TrimRight(c.Diff, "\n"))
+	}
+
+	prompt.WriteString("Respond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+func parseFixupResponse(content string, commitCount int) (int, string, error) {
+	var response fixupResponse
+	if err := json.Unmarshal([]byte(extractJSON(content)), &response); err != nil {
+		return -1, "", fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	// The prompt numbers commits from 1 and uses 0 for none
+	if response.Commit < 0 || response.Commit > commitCount {
+		return -1, "", fmt.Errorf("response names unknown commit %d", response.Commit)
+	}
+	return response.Commit - 1, strings.TrimSpace(response.Reason), nil
+}
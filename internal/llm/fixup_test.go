@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFixupResponse(t *testing.T) {
+	choice, reason, err := parseFixupResponse("```json\n{\"commit\": 2, \"reason\": \"Fixes the parser.\"}\n```", 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, choice)
+	require.Equal(t, "Fixes the parser.", reason)
+
+	choice, _, err = parseFixupResponse(`{"commit": 0, "reason": "Unrelated."}`, 3)
+	require.NoError(t, err)
+	require.Equal(t, -1, choice, "Zero means no matching commit")
+
+	_, _, err = parseFixupResponse(`{"commit": 4}`, 3)
+	require.Error(t, err)
+
+	_, _, err = parseFixupResponse("not json", 3)
+	require.Error(t, err)
+}
+
+func TestBuildFixupPromptNumbersCommits(t *testing.T) {
+	prompt := buildFixupPrompt("-a\n+b\n", []FixupCommit{{Subject: "feat: add a", Diff: "+a\n"}, {Subject: "docs: c", Diff: "+c\n"}})
+	require.Contains(t, prompt, "Staged changes:\n```diff\n-a\n+b\n```")
+	require.Contains(t, prompt, "Commit 1: feat: add a\n```diff\n+a\n```")
+	require.Contains(t, prompt, "Commit 2: docs: c")
+}
@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLedgerMaxSize is the size in bytes at which the ledger is rotated
+	DefaultLedgerMaxSize = 10 << 20
+	// DefaultLedgerMaxFiles is the number of rotated ledger files kept on disk
+	DefaultLedgerMaxFiles = 5
+)
+
+// LedgerEntry records the token usage and estimated cost of a single request
+type LedgerEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Repo      string    `json:"repo,omitempty"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Usage     Usage     `json:"usage"`
+	LatencyMS int64     `json:"latency_ms"`
+	Cost      float64   `json:"cost"`
+	// CostKnown is false when the model was missing from the price table
+	CostKnown bool `json:"cost_known"`
+}
+
+// Ledger is a local JSON lines file recording the usage of every request.
+// Unlike the audit log it holds no prompts or responses. Like it, the file is
+// rotated once it grows past MaxSize.
+type Ledger struct {
+	Path     string
+	MaxSize  int64
+	MaxFiles int
+
+	mu sync.Mutex
+}
+
+func NewLedger(path string) *Ledger {
+	return &Ledger{
+		Path:     path,
+		MaxSize:  DefaultLedgerMaxSize,
+		MaxFiles: DefaultLedgerMaxFiles,
+	}
+}
+
+// Record appends an entry to the ledger
+func (l *Ledger) Record(entry LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err = os.MkdirAll(filepath.Dir(l.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	if err = rotateIfFull(l.Path, int64(len(line)), l.MaxSize, l.MaxFiles, entry.Timestamp); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write(line); err != nil {
+		return fmt.Errorf("failed to write ledger entry: %w", err)
+	}
+	return nil
+}
+
+// Entries returns every recorded entry, including those in rotated files,
+// oldest first
+func (l *Ledger) Entries() ([]LedgerEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files, err := logFiles(l.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LedgerEntry
+	for _, file := range files {
+		fileEntries, err := readLedgerFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+func readLedgerFile(path string) ([]LedgerEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry LedgerEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	return entries, nil
+}
+
+// UsageTotal is the combined usage of a group of ledger entries
+type UsageTotal struct {
+	Key      string
+	Requests int
+	Usage    Usage
+	Cost     float64
+	// Unpriced counts the requests whose cost is unknown and missing from Cost
+	Unpriced int
+}
+
+// Aggregate groups entries by the key returned for each one and sums their
+// usage. The totals are sorted by key.
+func Aggregate(entries []LedgerEntry, key func(LedgerEntry) string) []UsageTotal {
+	byKey := map[string]*UsageTotal{}
+	for _, e := range entries {
+		k := key(e)
+		total, ok := byKey[k]
+		if !ok {
+			total = &UsageTotal{Key: k}
+			byKey[k] = total
+		}
+
+		total.Requests++
+		total.Usage.PromptTokens += e.Usage.PromptTokens
+		total.Usage.CompletionTokens += e.Usage.CompletionTokens
+		if e.CostKnown {
+			total.Cost += e.Cost
+		} else {
+			total.Unpriced++
+		}
+	}
+
+	totals := make([]UsageTotal, 0, len(byKey))
+	for _, total := range byKey {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Key < totals[j].Key })
+	return totals
+}
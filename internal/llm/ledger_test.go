@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCost(t *testing.T) {
+	usage := Usage{PromptTokens: 1000, CompletionTokens: 100}
+
+	cost, ok := EstimateCost("openrouter", "openai/gpt-4o", usage)
+	require.True(t, ok)
+	require.InDelta(t, 0.0035, cost, 1e-9)
+
+	cost, ok = EstimateCost("ollama", "mistral", usage)
+	require.True(t, ok, "local models are free")
+	require.Zero(t, cost)
+
+	cost, ok = EstimateCost("openrouter", "mistralai/mistral-small-3.1-24b-instruct:free", usage)
+	require.True(t, ok)
+	require.Zero(t, cost)
+
+	_, ok = EstimateCost("openrouter", "unknown/model", usage)
+	require.False(t, ok)
+}
+
+func TestGenerateSuggestionsRecordsUsage(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zeus-ledger-test-*")
+	require.NoError(t, err, "Failed to create temp directory")
+	defer os.RemoveAll(tmpDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"choices": [{"message": {"content": "{\"suggestions\": [{\"title\": \"feat: a\"}, {\"title\": \"fix: b\"}, {\"title\": \"chore: c\"}]}"}}],
+			"usage": {"prompt_tokens": 1000, "completion_tokens": 100}
+		}`))
+	}))
+	defer server.Close()
+
+	ledger := NewLedger(filepath.Join(tmpDir, "usage.jsonl"))
+	p := NewOpenRouterProvider("key", "openai/gpt-4o", WithBaseURL(server.URL), WithLedger(ledger, "/repo"))
+
+	result, err := p.GenerateSuggestions("diff", false, "conventional", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"feat: a", "fix: b", "chore: c"}, result.Suggestions)
+	require.Equal(t, Usage{PromptTokens: 1000, CompletionTokens: 100}, result.Usage)
+	require.True(t, result.CostKnown)
+	require.InDelta(t, 0.0035, result.Cost, 1e-9)
+
+	entries, err := ledger.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "/repo", entries[0].Repo)
+	require.Equal(t, "openai/gpt-4o", entries[0].Model)
+	require.Equal(t, 1000, entries[0].Usage.PromptTokens)
+	require.InDelta(t, 0.0035, entries[0].Cost, 1e-9)
+}
+
+func TestAggregate(t *testing.T) {
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []LedgerEntry{
+		{Timestamp: day, Model: "a", Usage: Usage{PromptTokens: 10, CompletionTokens: 1}, Cost: 0.5, CostKnown: true},
+		{Timestamp: day, Model: "b", Usage: Usage{PromptTokens: 20, CompletionTokens: 2}},
+		{Timestamp: day.Add(24 * time.Hour), Model: "a", Usage: Usage{PromptTokens: 30, CompletionTokens: 3}, Cost: 0.25, CostKnown: true},
+	}
+
+	byModel := Aggregate(entries, func(e LedgerEntry) string { return e.Model })
+	require.Equal(t, []UsageTotal{
+		{Key: "a", Requests: 2, Usage: Usage{PromptTokens: 40, CompletionTokens: 4}, Cost: 0.75},
+		{Key: "b", Requests: 1, Usage: Usage{PromptTokens: 20, CompletionTokens: 2}, Unpriced: 1},
+	}, byModel)
+
+	byDay := Aggregate(entries, func(e LedgerEntry) string { return e.Timestamp.Format(time.DateOnly) })
+	require.Len(t, byDay, 2)
+	require.Equal(t, "2025-03-01", byDay[0].Key)
+	require.Equal(t, 2, byDay[0].Requests)
+}
+
+func TestLedgerRotates(t *testing.T) {
+	tmpDir := t.TempDir()
+	ledger := NewLedger(filepath.Join(tmpDir, "usage.jsonl"))
+	ledger.MaxSize = 300
+	ledger.MaxFiles = 2
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, ledger.Record(LedgerEntry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Provider:  "ollama",
+			Model:     "mistral",
+			Usage:     Usage{PromptTokens: i},
+		}))
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(tmpDir, "usage-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 2, "Only MaxFiles rotated files are kept")
+
+	entries, err := ledger.Entries()
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.Less(t, len(entries), 10, "The oldest entries were dropped")
+	require.Equal(t, 9, entries[len(entries)-1].Usage.PromptTokens, "Entries span rotated files, oldest first")
+	for i := 1; i < len(entries); i++ {
+		require.True(t, entries[i-1].Timestamp.Before(entries[i].Timestamp))
+	}
+}
+
+func TestLedgerWriteFailureKeepsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "{\"ok\": true}", "prompt_eval_count": 12, "eval_count": 5}`))
+	}))
+	defer server.Close()
+
+	// A file where the ledger directory should be makes every write fail
+	blocker := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o600))
+	ledger := NewLedger(filepath.Join(blocker, "usage.jsonl"))
+	auditLog := NewAuditLog(filepath.Join(blocker, "audit"))
+
+	var warnings []error
+	p := NewOllamaProvider("mistral", WithBaseURL(server.URL), WithLedger(ledger, "/repo"), WithAuditLog(auditLog, "/repo"),
+		WithWarnings(func(err error) { warnings = append(warnings, err) }))
+
+	res, err := p.Complete("prompt")
+	require.NoError(t, err, "A failed ledger or audit write must not fail the request")
+	require.Equal(t, `{"ok": true}`, res.Content)
+	require.Len(t, warnings, 2)
+	require.ErrorContains(t, warnings[0], "audit log")
+	require.ErrorContains(t, warnings[1], "usage ledger")
+}
@@ -3,12 +3,44 @@ package llm
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 )
 
 // Provider is an interface for different LLM providers
 type Provider interface {
-	GenerateSuggestions(diff string, includeBody bool, style string) ([]string, error)
+	// GenerateSuggestions asks for suggestions for diff. hints are extra facts
+	// about the change, such as the ticket it belongs to, added to the prompt.
+	GenerateSuggestions(diff string, includeBody bool, style string, hints []string) (*Result, error)
+	// Complete sends a free-form prompt. Providers are asked to answer in
+	// JSON, so the prompt should describe the expected object.
+	Complete(prompt string) (*Response, error)
+	// Stream sends a free-form prompt and writes the plain-text answer to w
+	// as it arrives. The returned response holds the whole answer.
+	Stream(prompt string, w io.Writer) (*Response, error)
+}
+
+// Stats describes the token usage, latency and cost of a request
+type Stats struct {
+	Usage   Usage
+	Latency time.Duration
+	// Cost is the estimated cost in US dollars, see EstimateCost
+	Cost float64
+	// CostKnown is false when the model is missing from the price table
+	CostKnown bool
+}
+
+// Result holds the suggestions generated for a diff
+type Result struct {
+	Suggestions []string
+	Stats
+}
+
+// Response is the answer to a prompt sent with Complete
+type Response struct {
+	Content string
+	Stats
 }
 
 type Message struct {
@@ -25,18 +57,33 @@ type LLMResponse struct {
 	Suggestions []Suggestion `json:"suggestions"`
 }
 
-func NewProvider(providerType string, apiKey string, model string) (Provider, error) {
+func NewProvider(providerType string, apiKey string, model string, opts ...Option) (Provider, error) {
+	var (
+		provider Provider
+		endpoint string
+	)
+
 	switch strings.ToLower(providerType) {
 	case "ollama":
-		return NewOllamaProvider(model), nil
+		p := NewOllamaProvider(model, opts...)
+		provider, endpoint = p, p.BaseURL
 	case "openrouter":
-		return NewOpenRouterProvider(apiKey, model), nil
+		p := NewOpenRouterProvider(apiKey, model, opts...)
+		provider, endpoint = p, p.BaseURL
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", providerType)
 	}
+
+	if o := newOptions(opts); o.policy != nil {
+		if err := o.policy.check(strings.ToLower(providerType), endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return provider, nil
 }
 
-func buildPrompt(diff string, includeBody bool, style string) string {
+func buildPrompt(diff string, includeBody bool, style string, hints []string) string {
 	var prompt strings.Builder
 
 	prompt.WriteString(`You are a commit message generator. Analyze this git diff and respond with JSON containing exactly 3 commit message suggestions in the following format:
@@ -65,29 +112,60 @@ Git Diff:
 	prompt.WriteString(diff)
 	prompt.WriteString("\n```\n\n")
 
-	if style == "conventional" {
-		prompt.WriteString(`CONVENTIONAL COMMITS RULES:
+	writeHints(&prompt, hints)
+	writeStyleRules(&prompt, includeBody, style)
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+const conventionalRules = `CONVENTIONAL COMMITS RULES:
 - Title format: "type(scope): description"
 - Types: feat, fix, docs, style, refactor, test, chore
 - Scope: optional component name
 - Description: imperative mood, lowercase, no period
-`)
-	}
+`
 
-	if includeBody {
-		prompt.WriteString(`BODY REQUIREMENTS:
+const bodyRules = `BODY REQUIREMENTS:
 - Separate from title by blank line
 - Explain "what" and "why" not "how"
 - Wrap lines at 72 characters
-`)
+`
+
+// writeHints adds the facts about the change that the diff does not show
+func writeHints(prompt *strings.Builder, hints []string) {
+	if len(hints) == 0 {
+		return
 	}
 
-	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+	prompt.WriteString("CONTEXT:\n")
+	for _, hint := range hints {
+		fmt.Fprintf(prompt, "- %s\n", hint)
+	}
+}
 
-	return prompt.String()
+// writeStyleRules adds the commit message rules for the style and body options
+func writeStyleRules(prompt *strings.Builder, includeBody bool, style string) {
+	if style == "conventional" {
+		prompt.WriteString(conventionalRules)
+	}
+
+	if includeBody {
+		prompt.WriteString(bodyRules)
+	}
 }
 
-func parseJSONResponse(content string, includeBody bool) ([]string, error) {
+// formatMessage joins a title and body into a commit message
+func formatMessage(s Suggestion, includeBody bool) string {
+	if includeBody && s.Body != "" {
+		return fmt.Sprintf("%s\n\n%s", s.Title, s.Body)
+	}
+	return s.Title
+}
+
+// extractJSON strips markdown code fences that models sometimes wrap JSON in
+func extractJSON(content string) string {
 	jsonStart := strings.Index(content, "```json")
 	if jsonStart >= 0 {
 		content = content[jsonStart+7:]
@@ -98,7 +176,11 @@ func parseJSONResponse(content string, includeBody bool) ([]string, error) {
 		content = content[:jsonEnd]
 	}
 
-	content = strings.TrimSpace(content)
+	return strings.TrimSpace(content)
+}
+
+func parseJSONResponse(content string, includeBody bool) ([]string, error) {
+	content = extractJSON(content)
 
 	var response LLMResponse
 	if err := json.Unmarshal([]byte(content), &response); err != nil {
@@ -111,11 +193,7 @@ func parseJSONResponse(content string, includeBody bool) ([]string, error) {
 
 	var suggestions []string
 	for _, s := range response.Suggestions {
-		if includeBody && s.Body != "" {
-			suggestions = append(suggestions, fmt.Sprintf("%s\n\n%s", s.Title, s.Body))
-		} else {
-			suggestions = append(suggestions, s.Title)
-		}
+		suggestions = append(suggestions, formatMessage(s, includeBody))
 	}
 
 	return suggestions, nil
@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"fmt"
+	"time"
+)
+
+// Inspector is an optional Provider capability for discovering the models a
+// provider offers and checking that it answers. Check for it with a type
+// assertion.
+type Inspector interface {
+	// ListModels returns the models the provider offers
+	ListModels() ([]ModelInfo, error)
+	// Ping sends a tiny prompt to the configured model and returns the round trip time
+	Ping() (time.Duration, error)
+}
+
+// ModelInfo describes a model offered by a provider
+type ModelInfo struct {
+	ID string
+	// ContextLength is the maximum number of tokens, or 0 when unknown
+	ContextLength int
+	// Pricing is nil when the provider does not publish prices
+	Pricing *Pricing
+	// Size is the size on disk in bytes for local models, or 0 when unknown
+	Size int64
+}
+
+// Pricing is the cost of a model in US dollars per token
+type Pricing struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// Free reports whether the model costs nothing to use
+func (p Pricing) Free() bool {
+	return p.Prompt == 0 && p.Completion == 0
+}
+
+// pingPrompt is a tiny prompt used to measure the provider's latency
+const pingPrompt = `Respond with the JSON object {"ok": true} and nothing else.`
+
+// ping measures the time taken to complete pingPrompt
+func (o options) ping(c completer, provider, model string) (time.Duration, error) {
+	start := time.Now()
+	if _, err := o.send(c, provider, model, pingPrompt); err != nil {
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+var (
+	_ Inspector = (*OllamaProvider)(nil)
+	_ Inspector = (*OpenRouterProvider)(nil)
+)
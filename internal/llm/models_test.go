@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenRouterListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/models", r.URL.Path)
+		w.Write([]byte(`{"data": [
+			{"id": "paid/model", "context_length": 32768, "pricing": {"prompt": "0.000002", "completion": "0.000006"}},
+			{"id": "free/model:free", "context_length": 8192, "pricing": {"prompt": "0", "completion": "0"}},
+			{"id": "openrouter/auto", "context_length": 2000000, "pricing": {"prompt": "-1", "completion": "-1"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenRouterProvider("", "", WithBaseURL(server.URL))
+	models, err := p.ListModels()
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+
+	require.Equal(t, "paid/model", models[0].ID)
+	require.Equal(t, 32768, models[0].ContextLength)
+	require.NotNil(t, models[0].Pricing)
+	require.InDelta(t, 0.000002, models[0].Pricing.Prompt, 1e-12)
+	require.InDelta(t, 0.000006, models[0].Pricing.Completion, 1e-12)
+
+	require.NotNil(t, models[1].Pricing)
+	require.True(t, models[1].Pricing.Free())
+
+	require.Nil(t, models[2].Pricing, "variable pricing should be reported as unknown")
+}
+
+func TestOllamaListModelsAndPing(t *testing.T) {
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models": [{"name": "mistral:latest", "size": 4109865159}]}`))
+		case "/api/generate":
+			var req OllamaRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			prompt = req.Prompt
+			w.Write([]byte(`{"response": "{\"ok\": true}", "prompt_eval_count": 12, "eval_count": 5}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	p := NewOllamaProvider("mistral", WithBaseURL(server.URL))
+
+	models, err := p.ListModels()
+	require.NoError(t, err)
+	require.Equal(t, []ModelInfo{{ID: "mistral:latest", Size: 4109865159}}, models)
+
+	latency, err := p.Ping()
+	require.NoError(t, err)
+	require.Positive(t, latency)
+	require.Equal(t, pingPrompt, prompt)
+}
+
+func TestPingReportsProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := NewOllamaProvider("missing", WithBaseURL(server.URL))
+	_, err := p.Ping()
+	require.ErrorContains(t, err, "model not found")
+}
@@ -1,32 +1,48 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultOllamaURL is the address of a local Ollama server
+const DefaultOllamaURL = "http://localhost:11434"
+
 type OllamaProvider struct {
-	Model string
+	BaseURL string
+	Model   string
+
+	opts options
 }
 
-func NewOllamaProvider(model string) *OllamaProvider {
+func NewOllamaProvider(model string, opts ...Option) *OllamaProvider {
 	if model == "" {
 		model = "deepseek-coder"
 	}
 
+	o := newOptions(opts)
+	baseURL := o.baseURL
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+
 	return &OllamaProvider{
-		Model: model,
+		BaseURL: baseURL,
+		Model:   model,
+		opts:    o,
 	}
 }
 
 type OllamaRequest struct {
 	Model   string `json:"model"`
 	Prompt  string `json:"prompt"`
-	Format  string `json:"format"`
+	Format  string `json:"format,omitempty"`
 	Stream  bool   `json:"stream"`
 	Options struct {
 		Temperature float64 `json:"temperature"`
@@ -34,19 +50,125 @@ type OllamaRequest struct {
 }
 
 type OllamaResponse struct {
-	Response string `json:"response"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	Error           string `json:"error"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
-func (p *OllamaProvider) GenerateSuggestions(diff string, includeBody bool, style string) ([]string, error) {
+func (p *OllamaProvider) GenerateSuggestions(diff string, includeBody bool, style string, hints []string) (*Result, error) {
 	// Check if Ollama is running
-	_, err := http.Get("http://localhost:11434/api/version")
+	_, err := p.opts.client(10 * time.Second).Get(p.BaseURL + "/api/version")
 	if err != nil {
-		return nil, fmt.Errorf("ollama server not running at http://localhost:11434. Start Ollama or use a different provider")
+		return nil, fmt.Errorf("ollama server not running at %s. Start Ollama or use a different provider", p.BaseURL)
 	}
 
 	// Build the prompt
-	prompt := buildPrompt(diff, includeBody, style)
+	prompt := buildPrompt(diff, includeBody, style, hints)
+
+	res, err := p.opts.send(p, "ollama", p.Model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := parseJSONResponse(res.Content, includeBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Suggestions: suggestions, Stats: res.stats("ollama", p.Model)}, nil
+}
+
+// Complete sends a free-form prompt to the model
+func (p *OllamaProvider) Complete(prompt string) (*Response, error) {
+	return p.opts.respond(p, "ollama", p.Model, prompt)
+}
+
+// Stream sends a free-form prompt and writes the answer to w as it arrives
+func (p *OllamaProvider) Stream(prompt string, w io.Writer) (*Response, error) {
+	return p.opts.streamTo(p, "ollama", p.Model, prompt, w)
+}
+
+func (p *OllamaProvider) stream(prompt string, emit func(string) error) (*completion, error) {
+	reqBody := OllamaRequest{
+		Model:  p.Model,
+		Prompt: prompt,
+		Stream: true,
+	}
+	reqBody.Options.Temperature = 0.7
+
+	reqBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.BaseURL+"/api/generate", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Long answers take a while to stream, so allow more time than complete
+	client := p.opts.client(5 * time.Minute)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &completion{Raw: string(respBody)}, fmt.Errorf("ollama returned error: %s", string(respBody))
+	}
+
+	// The answer arrives as one JSON object per line, the last one with the
+	// token counts
+	var raw, content strings.Builder
+	res := &completion{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		raw.Write(line)
+		raw.WriteByte('\n')
 
+		var chunk OllamaResponse
+		if err = json.Unmarshal(line, &chunk); err != nil {
+			res.Raw = raw.String()
+			return res, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if chunk.Error != "" {
+			res.Raw = raw.String()
+			return res, fmt.Errorf("ollama returned error: %s", chunk.Error)
+		}
+
+		content.WriteString(chunk.Response)
+		if err = emit(chunk.Response); err != nil {
+			res.Raw = raw.String()
+			return res, fmt.Errorf("failed to write response: %w", err)
+		}
+		if chunk.Done {
+			res.Usage = Usage{
+				PromptTokens:     chunk.PromptEvalCount,
+				CompletionTokens: chunk.EvalCount,
+			}
+		}
+	}
+	res.Content = content.String()
+	res.Raw = raw.String()
+	if err = scanner.Err(); err != nil {
+		return res, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return res, nil
+}
+
+func (p *OllamaProvider) complete(prompt string) (*completion, error) {
 	// Create the request
 	reqBody := OllamaRequest{
 		Model:  p.Model,
@@ -66,16 +188,14 @@ func (p *OllamaProvider) GenerateSuggestions(diff string, includeBody bool, styl
 	}
 
 	// Make the API request
-	req, err := http.NewRequest(http.MethodPost, "http://localhost:11434/api/generate", bytes.NewBuffer(reqBytes))
+	req, err := http.NewRequest(http.MethodPost, p.BaseURL+"/api/generate", bytes.NewBuffer(reqBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := p.opts.client(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to Ollama: %w", err)
@@ -89,14 +209,59 @@ func (p *OllamaProvider) GenerateSuggestions(diff string, includeBody bool, styl
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama returned error: %s", string(respBody))
+		return &completion{Raw: string(respBody)}, fmt.Errorf("ollama returned error: %s", string(respBody))
 	}
 
 	var respObj OllamaResponse
 	err = json.Unmarshal(respBody, &respObj)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return &completion{Raw: string(respBody)}, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return parseJSONResponse(respObj.Response, includeBody)
+	return &completion{
+		Content: respObj.Response,
+		Raw:     string(respBody),
+		Usage: Usage{
+			PromptTokens:     respObj.PromptEvalCount,
+			CompletionTokens: respObj.EvalCount,
+		},
+	}, nil
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	} `json:"models"`
+}
+
+// ListModels returns the models installed on the Ollama server. It fails
+// quickly when no server is running.
+func (p *OllamaProvider) ListModels() ([]ModelInfo, error) {
+	client := p.opts.client(2 * time.Second)
+	resp, err := client.Get(p.BaseURL + "/api/tags")
+	if err != nil {
+		return nil, fmt.Errorf("ollama server not running at %s: %w", p.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %s", resp.Status)
+	}
+
+	var tags ollamaTagsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode model list: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, ModelInfo{ID: m.Name, Size: m.Size})
+	}
+	return models, nil
+}
+
+// Ping measures the time the model takes to answer a tiny prompt
+func (p *OllamaProvider) Ping() (time.Duration, error) {
+	return p.opts.ping(p, "ollama", p.Model)
 }
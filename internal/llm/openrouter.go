@@ -1,27 +1,44 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// DefaultOpenRouterURL is the base address of the OpenRouter API
+const DefaultOpenRouterURL = "https://openrouter.ai/api/v1"
+
 type OpenRouterProvider struct {
-	APIKey string
-	Model  string
+	BaseURL string
+	APIKey  string
+	Model   string
+
+	opts options
 }
 
-func NewOpenRouterProvider(apiKey string, model string) *OpenRouterProvider {
+func NewOpenRouterProvider(apiKey string, model string, opts ...Option) *OpenRouterProvider {
 	if model == "" {
 		model = "deepseek/deepseek-coder"
 	}
 
+	o := newOptions(opts)
+	baseURL := o.baseURL
+	if baseURL == "" {
+		baseURL = DefaultOpenRouterURL
+	}
+
 	return &OpenRouterProvider{
-		APIKey: apiKey,
-		Model:  model,
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Model:   model,
+		opts:    o,
 	}
 }
 
@@ -40,12 +57,154 @@ type OpenRouterResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// openRouterStreamRequest asks for a plain-text answer as server-sent events
+type openRouterStreamRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+	// Usage asks for the token counts in the last event
+	Usage struct {
+		Include bool `json:"include"`
+	} `json:"usage"`
+}
+
+type openRouterStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
-func (p *OpenRouterProvider) GenerateSuggestions(diff string, includeBody bool, style string) ([]string, error) {
+func (p *OpenRouterProvider) GenerateSuggestions(diff string, includeBody bool, style string, hints []string) (*Result, error) {
 	// Build the prompt
-	prompt := buildPrompt(diff, includeBody, style)
+	prompt := buildPrompt(diff, includeBody, style, hints)
+
+	res, err := p.opts.send(p, "openrouter", p.Model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response into individual suggestions
+	suggestions, err := parseJSONResponse(res.Content, includeBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Suggestions: suggestions, Stats: res.stats("openrouter", p.Model)}, nil
+}
+
+// Complete sends a free-form prompt to the model
+func (p *OpenRouterProvider) Complete(prompt string) (*Response, error) {
+	return p.opts.respond(p, "openrouter", p.Model, prompt)
+}
+
+// Stream sends a free-form prompt and writes the answer to w as it arrives
+func (p *OpenRouterProvider) Stream(prompt string, w io.Writer) (*Response, error) {
+	return p.opts.streamTo(p, "openrouter", p.Model, prompt, w)
+}
+
+func (p *OpenRouterProvider) stream(prompt string, emit func(string) error) (*completion, error) {
+	reqBody := openRouterStreamRequest{
+		Model:    p.Model,
+		Messages: []Message{{Role: "user", Content: prompt}},
+		Stream:   true,
+	}
+	reqBody.Usage.Include = true
+
+	reqBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	req.Header.Set("HTTP-Referer", "https://github.com/amosehiguese/zeus-ai")
+
+	// Long answers take a while to stream, so allow more time than complete
+	client := p.opts.client(5 * time.Minute)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &completion{Raw: string(respBody)}, fmt.Errorf("API returned error: %s", string(respBody))
+	}
+
+	// Events are "data: <json>" lines ending with "data: [DONE]"; lines
+	// starting with a colon are keep-alive comments
+	var raw, content strings.Builder
+	res := &completion{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		raw.WriteString(data)
+		raw.WriteByte('\n')
+
+		var chunk openRouterStreamChunk
+		if err = json.Unmarshal([]byte(data), &chunk); err != nil {
+			res.Raw = raw.String()
+			return res, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if chunk.Error != nil {
+			res.Raw = raw.String()
+			return res, fmt.Errorf("API returned error: %s", chunk.Error.Message)
+		}
+
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+			if err = emit(choice.Delta.Content); err != nil {
+				res.Raw = raw.String()
+				return res, fmt.Errorf("failed to write response: %w", err)
+			}
+		}
+		if chunk.Usage != nil {
+			res.Usage = Usage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+			}
+		}
+	}
+	res.Content = content.String()
+	res.Raw = raw.String()
+	if err = scanner.Err(); err != nil {
+		return res, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return res, nil
+}
+
+func (p *OpenRouterProvider) complete(prompt string) (*completion, error) {
 	// Create the request
 	reqBody := OpenRouterRequest{
 		Model: p.Model,
@@ -69,7 +228,7 @@ func (p *OpenRouterProvider) GenerateSuggestions(diff string, includeBody bool,
 	}
 
 	// Make the API request
-	req, err := http.NewRequest(http.MethodPost, "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(reqBytes))
+	req, err := http.NewRequest(http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewBuffer(reqBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -78,9 +237,7 @@ func (p *OpenRouterProvider) GenerateSuggestions(diff string, includeBody bool,
 	req.Header.Set("Authorization", "Bearer "+p.APIKey)
 	req.Header.Set("HTTP-Referer", "https://github.com/amosehiguese/zeus-ai")
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := p.opts.client(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -94,20 +251,109 @@ func (p *OpenRouterProvider) GenerateSuggestions(diff string, includeBody bool,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned error: %s", string(respBody))
+		return &completion{Raw: string(respBody)}, fmt.Errorf("API returned error: %s", string(respBody))
 	}
 
 	var respObj OpenRouterResponse
 	err = json.Unmarshal(respBody, &respObj)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return &completion{Raw: string(respBody)}, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if len(respObj.Choices) == 0 {
-		return nil, fmt.Errorf("API returned no suggestions")
+		return &completion{Raw: string(respBody)}, fmt.Errorf("API returned no suggestions")
 	}
 
-	// Parse the response into individual suggestions
-	content := respObj.Choices[0].Message.Content
-	return parseJSONResponse(content, includeBody)
+	return &completion{
+		Content: respObj.Choices[0].Message.Content,
+		Raw:     string(respBody),
+		Usage: Usage{
+			PromptTokens:     respObj.Usage.PromptTokens,
+			CompletionTokens: respObj.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+type openRouterModelsResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		ContextLength int    `json:"context_length"`
+		Pricing       struct {
+			Prompt     string `json:"prompt"`
+			Completion string `json:"completion"`
+		} `json:"pricing"`
+	} `json:"data"`
+}
+
+// ListModels returns the models available on OpenRouter with their context
+// length and pricing.
+func (p *OpenRouterProvider) ListModels() ([]ModelInfo, error) {
+	client := p.opts.client(10 * time.Second)
+	resp, err := client.Get(p.BaseURL + "/models")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", p.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %s", resp.Status)
+	}
+
+	var data openRouterModelsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode model list: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(data.Data))
+	for _, m := range data.Data {
+		info := ModelInfo{ID: m.ID, ContextLength: m.ContextLength}
+
+		// Prices are decimal strings in dollars per token; routers such as
+		// openrouter/auto report -1 because the price depends on the model picked
+		prompt, promptErr := strconv.ParseFloat(m.Pricing.Prompt, 64)
+		completion, completionErr := strconv.ParseFloat(m.Pricing.Completion, 64)
+		if promptErr == nil && completionErr == nil && prompt >= 0 && completion >= 0 {
+			info.Pricing = &Pricing{Prompt: prompt, Completion: completion}
+		}
+
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// Ping measures the time the model takes to answer a tiny prompt
+func (p *OpenRouterProvider) Ping() (time.Duration, error) {
+	return p.opts.ping(p, "openrouter", p.Model)
+}
+
+// CheckOpenRouterKey verifies that apiKey is accepted by OpenRouter. The
+// request passes the policy given with WithPolicy, like any provider request.
+func CheckOpenRouterKey(baseURL, apiKey string, opts ...Option) error {
+	if baseURL == "" {
+		baseURL = DefaultOpenRouterURL
+	}
+	if apiKey == "" {
+		return fmt.Errorf("no API key configured")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/key", http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := newOptions(opts).client(10 * time.Second).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("API key was rejected (%s)", resp.Status)
+	default:
+		return fmt.Errorf("API returned status %s", resp.Status)
+	}
 }
@@ -0,0 +1,206 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Option configures optional behaviour shared by every provider
+type Option func(*options)
+
+type options struct {
+	audit   *AuditLog
+	ledger  *Ledger
+	repo    string
+	policy  *Policy
+	baseURL string
+	warn    func(error)
+	// transport is shared by every request of a provider, see Policy.transport
+	transport *http.Transport
+}
+
+// WithAuditLog records every exchange with the provider in the given audit log.
+// repo identifies the repository the diff was taken from.
+func WithAuditLog(log *AuditLog, repo string) Option {
+	return func(o *options) {
+		o.audit = log
+		o.repo = repo
+	}
+}
+
+// WithLedger records the token usage and estimated cost of every request in
+// the given ledger. repo identifies the repository the diff was taken from.
+func WithLedger(ledger *Ledger, repo string) Option {
+	return func(o *options) {
+		o.ledger = ledger
+		o.repo = repo
+	}
+}
+
+// WithWarnings reports problems that do not fail a request, such as a failed
+// audit log or ledger write, to warn instead of standard error
+func WithWarnings(warn func(error)) Option {
+	return func(o *options) {
+		o.warn = warn
+	}
+}
+
+// WithBaseURL overrides the provider's default API endpoint
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.transport = o.policy.transport()
+	return o
+}
+
+// client returns an HTTP client that enforces the policy
+func (o options) client(timeout time.Duration) *http.Client {
+	c := &http.Client{Timeout: timeout}
+	if o.transport != nil {
+		c.Transport = o.transport
+		c.CheckRedirect = o.policy.checkRedirect
+	}
+	return c
+}
+
+// Usage holds the token counts reported by a provider for a single request
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// completion is the raw result of sending a single prompt to a provider
+type completion struct {
+	Content string
+	Raw     string
+	Usage   Usage
+	Latency time.Duration
+}
+
+// completer is implemented by every provider backend
+type completer interface {
+	complete(prompt string) (*completion, error)
+}
+
+// streamer is implemented by provider backends that can stream a plain-text
+// answer, calling emit with each piece as it arrives
+type streamer interface {
+	stream(prompt string, emit func(string) error) (*completion, error)
+}
+
+// send runs a prompt through the given backend and records the exchange in
+// the audit log and the usage ledger when they are configured.
+func (o options) send(c completer, provider, model, prompt string) (*completion, error) {
+	return o.exchange(provider, model, prompt, func() (*completion, error) {
+		return c.complete(prompt)
+	})
+}
+
+// exchange times run and records it like send
+func (o options) exchange(provider, model, prompt string, run func() (*completion, error)) (*completion, error) {
+	start := time.Now()
+	res, err := run()
+	latency := time.Since(start)
+	if res != nil {
+		res.Latency = latency
+	}
+
+	if o.audit != nil {
+		entry := AuditEntry{
+			Timestamp: start.UTC(),
+			Repo:      o.repo,
+			Provider:  provider,
+			Model:     model,
+			Prompt:    prompt,
+			LatencyMS: latency.Milliseconds(),
+		}
+		if res != nil {
+			entry.Response = res.Raw
+			entry.Usage = res.Usage
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		// The provider has answered, so a failed write must not discard the response
+		if auditErr := o.audit.Record(entry); auditErr != nil {
+			o.warning(fmt.Errorf("failed to write audit log: %w", auditErr))
+		}
+	}
+
+	if o.ledger != nil && err == nil {
+		cost, known := EstimateCost(provider, model, res.Usage)
+		ledgerErr := o.ledger.Record(LedgerEntry{
+			Timestamp: start.UTC(),
+			Repo:      o.repo,
+			Provider:  provider,
+			Model:     model,
+			Usage:     res.Usage,
+			LatencyMS: latency.Milliseconds(),
+			Cost:      cost,
+			CostKnown: known,
+		})
+		if ledgerErr != nil {
+			o.warning(fmt.Errorf("failed to write usage ledger: %w", ledgerErr))
+		}
+	}
+
+	return res, err
+}
+
+func (o options) warning(err error) {
+	if o.warn != nil {
+		o.warn(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+}
+
+// stats returns the usage, latency and estimated cost of a completion
+func (c *completion) stats(provider, model string) Stats {
+	cost, known := EstimateCost(provider, model, c.Usage)
+	return Stats{
+		Usage:     c.Usage,
+		Latency:   c.Latency,
+		Cost:      cost,
+		CostKnown: known,
+	}
+}
+
+// respond sends a free-form prompt for Provider.Complete
+func (o options) respond(c completer, provider, model, prompt string) (*Response, error) {
+	res, err := o.send(c, provider, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Content: res.Content, Stats: res.stats(provider, model)}, nil
+}
+
+// streamTo sends a free-form prompt for Provider.Stream, writing the answer
+// to w as it arrives
+func (o options) streamTo(s streamer, provider, model, prompt string, w io.Writer) (*Response, error) {
+	res, err := o.exchange(provider, model, prompt, func() (*completion, error) {
+		return s.stream(prompt, func(chunk string) error {
+			_, err := io.WriteString(w, chunk)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Content: res.Content, Stats: res.stats(provider, model)}, nil
+}
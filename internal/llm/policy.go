@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrPolicyViolation is returned by NewProvider when the requested provider is
+// not permitted by the configured policy.
+var ErrPolicyViolation = errors.New("policy violation")
+
+// Policy restricts which providers may be created
+type Policy struct {
+	// AllowedProviders lists the provider names that may be used. Empty allows all.
+	AllowedProviders []string
+	// LocalOnly rejects providers whose endpoint is not on a loopback or private address
+	LocalOnly bool
+	// Source describes where the policy was defined, for error messages
+	Source string
+}
+
+// WithPolicy makes NewProvider reject providers that the policy does not allow
+func WithPolicy(policy Policy) Option {
+	return func(o *options) {
+		o.policy = &policy
+	}
+}
+
+// lookupIP is replaced in tests
+var lookupIP = net.LookupIP
+
+// Check reports whether the policy allows a provider with the given endpoint,
+// or the provider's default endpoint when baseURL is empty. It lets callers
+// reject a provider before resolving its API key.
+func (p Policy) Check(providerType, baseURL string) error {
+	endpoint := strings.TrimRight(baseURL, "/")
+	if endpoint == "" {
+		switch strings.ToLower(providerType) {
+		case "ollama":
+			endpoint = DefaultOllamaURL
+		case "openrouter":
+			endpoint = DefaultOpenRouterURL
+		}
+	}
+	return p.check(strings.ToLower(providerType), endpoint)
+}
+
+func (p *Policy) check(providerType, endpoint string) error {
+	if len(p.AllowedProviders) > 0 {
+		allowed := false
+		for _, name := range p.AllowedProviders {
+			if strings.EqualFold(name, providerType) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: provider %q is not in allowed_providers [%s]%s",
+				ErrPolicyViolation, providerType, strings.Join(p.AllowedProviders, ", "), p.source())
+		}
+	}
+
+	if !p.LocalOnly {
+		return nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("%w: cannot determine host of endpoint %q for local-only network%s", ErrPolicyViolation, endpoint, p.source())
+	}
+
+	_, err = p.resolve(u.Hostname())
+	return err
+}
+
+func (p *Policy) source() string {
+	if p.Source == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (set in %s)", p.Source)
+}
+
+// resolve returns the addresses of host, failing unless every one of them is
+// a loopback or private address
+func (p *Policy) resolve(host string) ([]net.IP, error) {
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		ips, err = lookupIP(host)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot resolve %s for local-only network%s: %w", ErrPolicyViolation, host, p.source(), err)
+		}
+	}
+
+	for _, ip := range ips {
+		if !ip.IsLoopback() && !ip.IsPrivate() {
+			return nil, fmt.Errorf("%w: %s resolves to public address %s but network is local-only%s; the diff was not sent",
+				ErrPolicyViolation, host, ip, p.source())
+		}
+	}
+	return ips, nil
+}
+
+// transport returns the HTTP transport requests must go through, or nil for
+// the default one. Under a local-only network every address is checked when
+// the connection is made, so that a later DNS answer cannot point at a public
+// host, and proxies from the environment are never used.
+func (p *Policy) transport() *http.Transport {
+	if p == nil || !p.LocalOnly {
+		return nil
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			ips, err := p.resolve(host)
+			if err != nil {
+				return nil, err
+			}
+
+			// Dial the checked addresses rather than the name, which could
+			// resolve differently a second time
+			for _, ip := range ips {
+				var conn net.Conn
+				if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+					return conn, nil
+				}
+			}
+			return nil, err
+		},
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// checkRedirect refuses redirects to another host under a local-only network
+func (p *Policy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("%w: refusing redirect from %s to %s because network is local-only%s; the diff was not sent",
+			ErrPolicyViolation, via[0].URL.Host, req.URL.Host, p.source())
+	}
+	return nil
+}
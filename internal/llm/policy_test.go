@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProviderRejectsDisallowedProvider(t *testing.T) {
+	_, err := NewProvider("openrouter", "key", "", WithPolicy(Policy{
+		AllowedProviders: []string{"ollama"},
+		Source:           "/repo/.zeusrc",
+	}))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	require.Contains(t, err.Error(), "/repo/.zeusrc")
+
+	_, err = NewProvider("ollama", "", "", WithPolicy(Policy{AllowedProviders: []string{"ollama"}}))
+	require.NoError(t, err)
+}
+
+func TestNewProviderLocalOnly(t *testing.T) {
+	original := lookupIP
+	defer func() { lookupIP = original }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "localhost":
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		case "ollama.lan":
+			return []net.IP{net.ParseIP("192.168.1.20")}, nil
+		default:
+			return []net.IP{net.ParseIP("104.18.2.115")}, nil
+		}
+	}
+
+	policy := WithPolicy(Policy{LocalOnly: true})
+
+	_, err := NewProvider("ollama", "", "", policy)
+	require.NoError(t, err, "localhost should be allowed")
+
+	_, err = NewProvider("ollama", "", "", policy, WithBaseURL("http://ollama.lan:11434"))
+	require.NoError(t, err, "private address should be allowed")
+
+	_, err = NewProvider("openrouter", "key", "", policy)
+	require.ErrorIs(t, err, ErrPolicyViolation)
+
+	_, err = NewProvider("ollama", "", "", policy, WithBaseURL("http://8.8.8.8:11434"))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+}
+
+func TestLocalOnlyCheckedOnEveryConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "{}"}`))
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	original := lookupIP
+	defer func() { lookupIP = original }()
+	lookups := 0
+	lookupIP = func(host string) ([]net.IP, error) {
+		// The first answer passes the check in NewProvider, later ones rebind
+		// the name to a public address
+		lookups++
+		if lookups == 1 {
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		}
+		return []net.IP{net.ParseIP("104.18.2.115")}, nil
+	}
+
+	p, err := NewProvider("ollama", "", "", WithPolicy(Policy{LocalOnly: true}), WithBaseURL("http://ollama.test:"+port))
+	require.NoError(t, err)
+	_, err = p.Complete("prompt")
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	require.Contains(t, err.Error(), "104.18.2.115")
+}
+
+func TestCheckOpenRouterKeyFollowsPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	original := lookupIP
+	defer func() { lookupIP = original }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("104.18.2.115")}, nil
+	}
+
+	err = CheckOpenRouterKey("http://openrouter.test:"+port, "sk-secret", WithPolicy(Policy{LocalOnly: true}))
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	require.Zero(t, requests, "The key must not be sent")
+}
+
+func TestLocalOnlyRefusesRedirects(t *testing.T) {
+	var target *httptest.Server
+	target = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response": "{}"}`))
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(target.URL, "127.0.0.1", "localhost", 1)+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	p := NewOllamaProvider("", WithPolicy(Policy{LocalOnly: true}), WithBaseURL(server.URL))
+	_, err := p.Complete("prompt")
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	require.Contains(t, err.Error(), "refusing redirect")
+
+	transport := p.opts.transport
+	require.NotNil(t, transport)
+	require.Nil(t, transport.Proxy, "Proxies from the environment are never used")
+
+	p = NewOllamaProvider("", WithBaseURL(server.URL))
+	_, err = p.Complete("prompt")
+	require.NoError(t, err, "Redirects are followed without the policy")
+}
+
+func TestPolicyCheckUsesDefaultEndpoint(t *testing.T) {
+	original := lookupIP
+	defer func() { lookupIP = original }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		if host == "localhost" {
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		}
+		return []net.IP{net.ParseIP("104.18.2.115")}, nil
+	}
+
+	policy := Policy{LocalOnly: true}
+	require.NoError(t, policy.Check("ollama", ""))
+	require.ErrorIs(t, policy.Check("openrouter", ""), ErrPolicyViolation)
+	require.ErrorIs(t, Policy{AllowedProviders: []string{"ollama"}}.Check("OpenRouter", ""), ErrPolicyViolation)
+}
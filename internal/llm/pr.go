@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PullRequest is a generated pull request title and markdown description
+type PullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// String returns the title followed by a blank line and the body
+func (pr PullRequest) String() string {
+	return pr.Title + "\n\n" + pr.Body
+}
+
+// defaultPRSkeleton is used when the repository has no pull request template
+const defaultPRSkeleton = `## Summary
+
+## Changes
+
+## Testing
+
+## Risks
+`
+
+// DescribePullRequest asks the provider for a pull request title and body
+// from the messages of its commits and their combined diff. The body follows
+// template, or a summary, changes, testing and risks skeleton when it is empty.
+func DescribePullRequest(p Provider, commits []string, diff, template string, hints []string) (*PullRequest, *Response, error) {
+	res, err := p.Complete(buildPRPrompt(commits, diff, template, hints))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pr, err := parsePRResponse(res.Content)
+	if err != nil {
+		return nil, res, err
+	}
+	return pr, res, nil
+}
+
+func buildPRPrompt(commits []string, diff, template string, hints []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are a pull request description generator. Analyze the commits and the combined diff of a branch and respond with JSON in the following format:
+
+{
+  "title": "pull request title",
+  "body": "markdown description"
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+2. The title is a single line of at most 72 characters in imperative mood
+3. The body is markdown that fills in the skeleton below, keeping its headings in order
+4. Replace placeholder comments in the skeleton with content and keep checklists as they are
+5. Summarize what changed and why, how it was or can be tested, and what could break
+6. Escape all special JSON characters
+7. Do NOT include the diff in your response
+8. Do NOT include any commentary outside the JSON
+
+`)
+
+	prompt.WriteString("Commits, oldest first:\n")
+	for i, c := range commits {
+		fmt.Fprintf(&prompt, "%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(c), "\n", "\n   "))
+	}
+
+	fmt.Fprintf(&prompt, "\nCombined diff:\n```diff\n%s\n```\n\n", strings.TrimRight(diff, "\n"))
+
+	if strings.TrimSpace(template) == "" {
+		template = defaultPRSkeleton
+	}
+	fmt.Fprintf(&prompt, "Body skeleton:\n```markdown\n%s\n```\n\n", strings.TrimRight(template, "\n"))
+
+	writeHints(&prompt, hints)
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary.")
+
+	return prompt.String()
+}
+
+func parsePRResponse(content string) (*PullRequest, error) {
+	// The body may hold code fences of its own, which extractJSON would cut
+	// at, so plain JSON is tried first
+	var pr PullRequest
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &pr); err != nil {
+		if err = json.Unmarshal([]byte(extractJSON(content)), &pr); err != nil {
+			return nil, fmt.Errorf("invalid JSON response: %w", err)
+		}
+	}
+
+	pr.Title = strings.TrimSpace(pr.Title)
+	pr.Body = strings.TrimSpace(pr.Body)
+	if pr.Title == "" {
+		return nil, fmt.Errorf("response contains no title")
+	}
+	return &pr, nil
+}
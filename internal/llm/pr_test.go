@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePRResponse(t *testing.T) {
+	content := `{"title": " Add refunds ", "body": "## Summary\n\nRefunds.\n\n` + "```go\\nrefund()\\n```" + `"}`
+	pr, err := parsePRResponse(content)
+	require.NoError(t, err)
+	require.Equal(t, "Add refunds", pr.Title)
+	require.Equal(t, "## Summary\n\nRefunds.\n\n```go\nrefund()\n```", pr.Body, "Code fences in the body are kept")
+	require.Equal(t, "Add refunds\n\n"+pr.Body, pr.String())
+
+	pr, err = parsePRResponse("```json\n{\"title\": \"Fix\", \"body\": \"b\"}\n```")
+	require.NoError(t, err)
+	require.Equal(t, "Fix", pr.Title)
+
+	_, err = parsePRResponse(`{"body": "b"}`)
+	require.Error(t, err)
+}
+
+func TestBuildPRPromptUsesTemplate(t *testing.T) {
+	prompt := buildPRPrompt([]string{"feat: add refunds\n\nBody.", "fix: b"}, "+a\n", "", nil)
+	require.Contains(t, prompt, "1. feat: add refunds\n   \n   Body.\n2. fix: b\n")
+	require.Contains(t, prompt, "## Risks")
+
+	prompt = buildPRPrompt([]string{"fix: b"}, "+a\n", "## What\n<!-- describe -->\n", nil)
+	require.Contains(t, prompt, "```markdown\n## What\n<!-- describe -->\n```")
+	require.NotContains(t, prompt, "## Risks")
+}
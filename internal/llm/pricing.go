@@ -0,0 +1,34 @@
+package llm
+
+import "strings"
+
+// PriceTable holds the published prices of common hosted models, in US
+// dollars per token, used to estimate the cost of a request. Models served
+// locally by Ollama and OpenRouter models with a ":free" suffix cost nothing.
+var PriceTable = map[string]Pricing{
+	"anthropic/claude-3.5-haiku":               {Prompt: 0.8e-6, Completion: 4e-6},
+	"anthropic/claude-3.5-sonnet":              {Prompt: 3e-6, Completion: 15e-6},
+	"deepseek/deepseek-chat":                   {Prompt: 0.38e-6, Completion: 0.89e-6},
+	"deepseek/deepseek-coder":                  {Prompt: 0.04e-6, Completion: 0.12e-6},
+	"google/gemini-2.0-flash-001":              {Prompt: 0.1e-6, Completion: 0.4e-6},
+	"meta-llama/llama-3.1-8b-instruct":         {Prompt: 0.02e-6, Completion: 0.05e-6},
+	"meta-llama/llama-3.3-70b-instruct":        {Prompt: 0.12e-6, Completion: 0.3e-6},
+	"mistralai/mistral-small-3.1-24b-instruct": {Prompt: 0.05e-6, Completion: 0.15e-6},
+	"openai/gpt-4o":                            {Prompt: 2.5e-6, Completion: 10e-6},
+	"openai/gpt-4o-mini":                       {Prompt: 0.15e-6, Completion: 0.6e-6},
+}
+
+// EstimateCost returns the estimated cost in US dollars of a request with the
+// given usage. The second result is false when the model's price is unknown.
+func EstimateCost(provider, model string, usage Usage) (float64, bool) {
+	if strings.EqualFold(provider, "ollama") || strings.HasSuffix(model, ":free") {
+		return 0, true
+	}
+
+	pricing, ok := PriceTable[model]
+	if !ok {
+		return 0, false
+	}
+
+	return float64(usage.PromptTokens)*pricing.Prompt + float64(usage.CompletionTokens)*pricing.Completion, true
+}
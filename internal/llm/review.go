@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severities are the severities of review findings, from least to most severe
+var Severities = []string{"info", "low", "medium", "high", "critical"}
+
+// Categories are the categories review findings are asked to use
+var Categories = []string{"bug", "security", "performance", "error-handling", "concurrency", "maintainability", "style", "testing", "documentation"}
+
+// Finding is an issue found while reviewing a diff
+type Finding struct {
+	File string `json:"file"`
+	// Line is the line in the changed file, or 0 when the finding is about
+	// the file as a whole
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+type reviewResponse struct {
+	Findings []Finding `json:"findings"`
+}
+
+// ReviewChanges asks the provider to review diff and returns its findings
+func ReviewChanges(p Provider, diff string, hints []string) ([]Finding, *Response, error) {
+	res, err := p.Complete(buildReviewPrompt(diff, hints))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	findings, err := parseReviewResponse(res.Content)
+	if err != nil {
+		return nil, res, err
+	}
+	return findings, res, nil
+}
+
+func buildReviewPrompt(diff string, hints []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are a code reviewer. Review the changes in this git diff and respond with JSON in the following format:
+
+{
+  "findings": [
+    {
+      "file": "path/of/the/file",
+      "line": 42,
+      "severity": "medium",
+      "category": "bug",
+      "message": "what is wrong and how to fix it"
+    }
+  ]
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+`)
+	fmt.Fprintf(&prompt, "2. severity is one of: %s\n", strings.Join(Severities, ", "))
+	fmt.Fprintf(&prompt, "3. category is one of: %s\n", strings.Join(Categories, ", "))
+	prompt.WriteString(`4. line is the number shown before a line of the diff, which is its line in the changed file; use 0 for the file as a whole
+5. Only report real problems in the added or changed lines, not praise or summaries
+6. Return an empty findings list when there is nothing to report
+7. Escape all special JSON characters
+8. Do NOT include any commentary outside the JSON
+
+Git Diff, with the line numbers of the changed files:
+`)
+	prompt.WriteString("```diff\n")
+	prompt.WriteString(numberDiff(diff))
+	prompt.WriteString("\n```\n\n")
+
+	writeHints(&prompt, hints)
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+// numberDiff prefixes the added and context lines of diff with their line in
+// the changed file, so that findings can point at them
+func numberDiff(diff string) string {
+	var b strings.Builder
+	line := 0
+	inHunk := false
+	for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			inHunk = true
+			line = newStart(l)
+			b.WriteString(l)
+		case strings.HasPrefix(l, "diff --git"):
+			inHunk = false
+			b.WriteString(l)
+		case inHunk && strings.HasPrefix(l, "-"):
+			fmt.Fprintf(&b, "%6s %s", "", l)
+		case inHunk && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, " ") || l == ""):
+			fmt.Fprintf(&b, "%6d %s", line, l)
+			line++
+		default:
+			b.WriteString(l)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// newStart returns the first line of the changed file in a hunk header
+func newStart(header string) int {
+	var start int
+	if _, ranges, ok := strings.Cut(header, " +"); ok {
+		fmt.Sscanf(ranges, "%d", &start)
+	}
+	return start
+}
+
+func parseReviewResponse(content string) ([]Finding, error) {
+	var response reviewResponse
+	if err := json.Unmarshal([]byte(extractJSON(content)), &response); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(response.Findings))
+	for _, f := range response.Findings {
+		f.File = strings.TrimPrefix(strings.TrimSpace(f.File), "b/")
+		f.Message = strings.TrimSpace(f.Message)
+		f.Category = strings.ToLower(strings.TrimSpace(f.Category))
+		f.Severity = strings.ToLower(strings.TrimSpace(f.Severity))
+		if f.Message == "" {
+			continue
+		}
+		if f.Line < 0 {
+			f.Line = 0
+		}
+		// Unknown severities count as medium so that the gate still sees them
+		if !containsString(Severities, f.Severity) {
+			f.Severity = "medium"
+		}
+		if f.Category == "" {
+			f.Category = "maintainability"
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
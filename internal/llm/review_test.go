@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumberDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@ func main() {
+ 	a()
+-	b()
++	c()
++	d()
+ }
+`
+	require.Equal(t, `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@ func main() {
+    10  	a()
+       -	b()
+    11 +	c()
+    12 +	d()
+    13  }
+`, numberDiff(diff))
+}
+
+func TestParseReviewResponse(t *testing.T) {
+	content := "```json\n" + `{"findings": [
+  {"file": "b/main.go", "line": 11, "severity": "HIGH", "category": "Bug", "message": " c() can panic "},
+  {"file": "main.go", "line": -3, "severity": "blocker", "category": "", "message": "unclear"},
+  {"file": "main.go", "line": 12, "severity": "low", "category": "style", "message": ""}
+]}` + "\n```"
+
+	findings, err := parseReviewResponse(content)
+	require.NoError(t, err)
+	require.Equal(t, []Finding{
+		{File: "main.go", Line: 11, Severity: "high", Category: "bug", Message: "c() can panic"},
+		{File: "main.go", Line: 0, Severity: "medium", Category: "maintainability", Message: "unclear"},
+	}, findings, "Unknown severities count as medium and empty messages are dropped")
+
+	findings, err = parseReviewResponse(`{"findings": []}`)
+	require.NoError(t, err)
+	require.Empty(t, findings)
+
+	_, err = parseReviewResponse("looks good to me")
+	require.Error(t, err)
+}
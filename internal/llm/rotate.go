@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rotatedPattern matches the rotated copies of the active JSON lines file
+// active, such as audit-20240102T150405.000000000.jsonl for audit.jsonl
+func rotatedPattern(active string) string {
+	return strings.TrimSuffix(active, ".jsonl") + "-*.jsonl"
+}
+
+// rotateIfFull moves active aside when adding size bytes would grow it past
+// maxSize, then drops the oldest rotated files beyond maxFiles
+func rotateIfFull(active string, size, maxSize int64, maxFiles int, now time.Time) error {
+	info, err := os.Stat(active)
+	if err != nil || maxSize <= 0 || info.Size()+size <= maxSize {
+		return nil
+	}
+
+	name := filepath.Base(strings.TrimSuffix(active, ".jsonl"))
+	rotated := strings.TrimSuffix(active, ".jsonl") + "-" + now.UTC().Format("20060102T150405.000000000") + ".jsonl"
+	if err = os.Rename(active, rotated); err != nil {
+		return fmt.Errorf("failed to rotate %s file: %w", name, err)
+	}
+
+	files, err := logFiles(active)
+	if err != nil {
+		return err
+	}
+
+	// The active file was just moved aside, so every file here is a rotated one
+	for len(files) > maxFiles && maxFiles > 0 {
+		if err = os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to remove old %s file: %w", name, err)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// logFiles returns the rotated copies of active followed by active itself,
+// oldest first, skipping those that do not exist
+func logFiles(active string) ([]string, error) {
+	name := filepath.Base(strings.TrimSuffix(active, ".jsonl"))
+	rotated, err := filepath.Glob(rotatedPattern(active))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s files: %w", name, err)
+	}
+	sort.Strings(rotated)
+
+	if _, err = os.Stat(active); err == nil {
+		rotated = append(rotated, active)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to stat %s file: %w", name, err)
+	}
+
+	return rotated, nil
+}
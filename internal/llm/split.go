@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CommitGroup is a set of hunks proposed as a single commit
+type CommitGroup struct {
+	// Hunks are indexes into the hunks passed to SplitChanges
+	Hunks   []int
+	Message string
+}
+
+type splitResponse struct {
+	Commits []struct {
+		Hunks []int `json:"hunks"`
+		Suggestion
+	} `json:"commits"`
+}
+
+// SplitChanges asks the provider to group the hunks of a diff into logical,
+// atomic commits and to write a message for each. Every hunk ends up in
+// exactly one group; hunks the model leaves out are collected in a final
+// group without a message.
+func SplitChanges(p Provider, hunks []string, includeBody bool, style string, hints []string) ([]CommitGroup, *Response, error) {
+	res, err := p.Complete(buildSplitPrompt(hunks, includeBody, style, hints))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, err := parseSplitResponse(res.Content, len(hunks), includeBody)
+	if err != nil {
+		return nil, res, err
+	}
+	return groups, res, nil
+}
+
+func buildSplitPrompt(hunks []string, includeBody bool, style string, hints []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(`You are a commit message generator. The staged changes below mix unrelated work. Group the numbered hunks into logical, atomic commits and write a commit message for each group. Respond with JSON in the following format:
+
+{
+  "commits": [
+    {
+      "hunks": [1, 3],
+      "title": "commit title",
+      "body": "commit body (optional)"
+    }
+  ]
+}
+
+STRICT REQUIREMENTS:
+1. Response must be valid JSON
+2. Every hunk number must appear in exactly one commit
+3. Keep related changes, such as a function and its callers or tests, in the same commit
+4. Order the commits so that each one builds on the previous ones
+5. Omit "body" field when not requested
+6. Do NOT include the diff in your response
+7. Do NOT include any commentary or markdown
+
+`)
+
+	for i, hunk := range hunks {
+		fmt.Fprintf(&prompt, "Hunk %d:\n```diff\n%s\n```\n\n", i+1, strings.TrimRight(hunk, "\n"))
+	}
+
+	writeHints(&prompt, hints)
+	writeStyleRules(&prompt, includeBody, style)
+
+	prompt.WriteString("\nRespond ONLY with valid JSON in this exact format. Do not include any commentary or markdown.")
+
+	return prompt.String()
+}
+
+func parseSplitResponse(content string, hunkCount int, includeBody bool) ([]CommitGroup, error) {
+	var response splitResponse
+	if err := json.Unmarshal([]byte(extractJSON(content)), &response); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	assigned := make([]bool, hunkCount)
+	var groups []CommitGroup
+	for _, c := range response.Commits {
+		group := CommitGroup{Message: formatMessage(c.Suggestion, includeBody)}
+		for _, n := range c.Hunks {
+			// The prompt numbers hunks from 1; ignore unknown and repeated ones
+			if n < 1 || n > hunkCount || assigned[n-1] {
+				continue
+			}
+			assigned[n-1] = true
+			group.Hunks = append(group.Hunks, n-1)
+		}
+		if len(group.Hunks) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	var rest CommitGroup
+	for i, ok := range assigned {
+		if !ok {
+			rest.Hunks = append(rest.Hunks, i)
+		}
+	}
+	if len(rest.Hunks) > 0 {
+		groups = append(groups, rest)
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("response contains no commits")
+	}
+	return groups, nil
+}
@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSplitResponse(t *testing.T) {
+	content := "```json\n" + `{"commits": [
+		{"hunks": [1, 3], "title": "feat: add parser", "body": "Parse hunks."},
+		{"hunks": [3, 9, 2], "title": "docs: update readme"}
+	]}` + "\n```"
+
+	groups, err := parseSplitResponse(content, 4, true)
+	require.NoError(t, err)
+	require.Equal(t, []CommitGroup{
+		{Hunks: []int{0, 2}, Message: "feat: add parser\n\nParse hunks."},
+		{Hunks: []int{1}, Message: "docs: update readme"},
+		{Hunks: []int{3}},
+	}, groups, "Repeated and unknown hunks are dropped and missing ones collected last")
+
+	groups, err = parseSplitResponse(`{"commits": [{"hunks": [1], "title": "fix: a", "body": "b"}]}`, 1, false)
+	require.NoError(t, err)
+	require.Equal(t, "fix: a", groups[0].Message)
+
+	_, err = parseSplitResponse("not json", 1, false)
+	require.Error(t, err)
+}
+
+func TestBuildSplitPromptNumbersHunks(t *testing.T) {
+	prompt := buildSplitPrompt([]string{"@@ -1 +1 @@\n-a\n+b\n", "@@ -5 +5 @@\n-c\n+d\n"}, false, "conventional", nil)
+	require.Contains(t, prompt, "Hunk 1:\n```diff\n@@ -1 +1 @@\n-a\n+b\n```")
+	require.Contains(t, prompt, "Hunk 2:")
+	require.Contains(t, prompt, "CONVENTIONAL COMMITS RULES")
+	require.NotContains(t, prompt, "BODY REQUIREMENTS")
+}
+
+func TestBuildSplitPromptIncludesHints(t *testing.T) {
+	prompt := buildSplitPrompt([]string{"@@ -1 +1 @@\n-a\n+b\n"}, false, "simple", []string{"The changes belong to ticket PAY-1"})
+	require.Contains(t, prompt, "CONTEXT:\n- The changes belong to ticket PAY-1\n")
+}
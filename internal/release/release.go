@@ -0,0 +1,205 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/changelog"
+)
+
+// Version is a semantic version, keeping the "v" prefix of its tag if any
+type Version struct {
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+var versionPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion reads a tag such as v1.2.3 or 1.2.3-rc.1. Build metadata is
+// dropped.
+func ParseVersion(tag string) (Version, bool) {
+	m := versionPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return Version{}, false
+	}
+
+	v := Version{Prefix: m[1], Prerelease: m[5]}
+	v.Major, _ = strconv.Atoi(m[2])
+	v.Minor, _ = strconv.Atoi(m[3])
+	v.Patch, _ = strconv.Atoi(m[4])
+	return v, true
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Less compares the major, minor and patch numbers
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+// Latest returns the highest release among tags. Tags that are not semantic
+// versions and prereleases are ignored.
+func Latest(tags []string) (Version, string, bool) {
+	var latest Version
+	var latestTag string
+	for _, tag := range tags {
+		v, ok := ParseVersion(tag)
+		if !ok || v.Prerelease != "" {
+			continue
+		}
+		if latestTag == "" || latest.Less(v) {
+			latest, latestTag = v, tag
+		}
+	}
+	return latest, latestTag, latestTag != ""
+}
+
+// Bump is the part of a version a release increments
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Apply returns the release after v with the given bump
+func (v Version) Apply(b Bump) Version {
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch b {
+	case BumpMajor:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case BumpMinor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	case BumpPatch:
+		next.Patch++
+	}
+	return next
+}
+
+// Recommendation is the next version for a set of commits and why
+type Recommendation struct {
+	Current Version
+	Next    Version
+	Bump    Bump
+	Reasons []string
+}
+
+// Recommend picks the bump for the commits since current: major for breaking
+// changes, minor for features and patch for anything else. Before 1.0.0,
+// breaking changes bump the minor version instead.
+func Recommend(current Version, entries []changelog.Entry) Recommendation {
+	var breaking, features, fixes []changelog.Entry
+	for _, e := range entries {
+		switch {
+		case e.Breaking:
+			breaking = append(breaking, e)
+		case e.Conventional && e.Type == "feat":
+			features = append(features, e)
+		case e.Conventional && (e.Type == "fix" || e.Type == "perf" || e.Type == "revert"):
+			fixes = append(fixes, e)
+		}
+	}
+	other := len(entries) - len(breaking) - len(features) - len(fixes)
+
+	r := Recommendation{Current: current}
+	switch {
+	case len(breaking) > 0:
+		r.Bump = BumpMajor
+		r.Reasons = listReasons("breaking change", breaking)
+		r.Reasons = appendCounts(r.Reasons, count(len(features), "feature"), count(len(fixes), "fix"), count(other, "other commit"))
+		if current.Major == 0 {
+			r.Bump = BumpMinor
+			r.Reasons = append(r.Reasons, "breaking changes bump the minor version before 1.0.0")
+		}
+	case len(features) > 0:
+		r.Bump = BumpMinor
+		r.Reasons = listReasons("feature", features)
+		r.Reasons = appendCounts(r.Reasons, count(len(fixes), "fix"), count(other, "other commit"))
+	case len(fixes) > 0:
+		r.Bump = BumpPatch
+		r.Reasons = listReasons("fix", fixes)
+		r.Reasons = appendCounts(r.Reasons, count(other, "other commit"))
+	case other > 0:
+		r.Bump = BumpPatch
+		r.Reasons = []string{fmt.Sprintf("no features or fixes, only %s", count(other, "other commit"))}
+	}
+
+	r.Next = current.Apply(r.Bump)
+	return r
+}
+
+func listReasons(kind string, entries []changelog.Entry) []string {
+	reasons := make([]string, len(entries))
+	for i, e := range entries {
+		desc := e.Description
+		if e.Scope != "" {
+			desc = e.Scope + ": " + desc
+		}
+		reasons[i] = fmt.Sprintf("%s: %s (%.7s)", kind, desc, e.Hash)
+	}
+	return reasons
+}
+
+// appendCounts adds a line summarizing the commits that did not decide the
+// bump, skipping empty counts
+func appendCounts(reasons []string, counts ...string) []string {
+	var parts []string
+	for _, c := range counts {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return reasons
+	case 1:
+		return append(reasons, "also "+parts[0])
+	default:
+		return append(reasons, "also "+strings.Join(parts[:len(parts)-1], ", ")+" and "+parts[len(parts)-1])
+	}
+}
+
+func count(n int, noun string) string {
+	switch {
+	case n == 0:
+		return ""
+	case n == 1:
+		return "1 " + noun
+	case strings.HasSuffix(noun, "x"):
+		return fmt.Sprintf("%d %ses", n, noun)
+	default:
+		return fmt.Sprintf("%d %ss", n, noun)
+	}
+}
@@ -0,0 +1,74 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amosehiguese/zeus-ai/internal/changelog"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, ok := ParseVersion("v1.2.3")
+	require.True(t, ok)
+	require.Equal(t, Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3}, v)
+	require.Equal(t, "v1.2.3", v.String())
+
+	v, ok = ParseVersion("2.0.0-rc.1+build.5")
+	require.True(t, ok)
+	require.Equal(t, "2.0.0-rc.1", v.String())
+
+	for _, tag := range []string{"v1.2", "release-1.2.3", "v01.2.3", "latest"} {
+		_, ok = ParseVersion(tag)
+		require.False(t, ok, tag)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	v, tag, ok := Latest([]string{"v1.9.0", "v1.10.0", "v2.0.0-rc.1", "nightly", "v1.2.3"})
+	require.True(t, ok)
+	require.Equal(t, "v1.10.0", tag, "Versions compare numerically and prereleases are skipped")
+	require.Equal(t, 10, v.Minor)
+
+	_, _, ok = Latest([]string{"nightly"})
+	require.False(t, ok)
+}
+
+func TestRecommend(t *testing.T) {
+	current := Version{Prefix: "v", Major: 1, Minor: 4, Patch: 2}
+	entry := changelog.NewEntry
+
+	r := Recommend(current, []changelog.Entry{
+		entry("aaaaaaa1", "feat(api)!: drop v1 endpoints"),
+		entry("bbbbbbb2", "feat: add dark mode"),
+		entry("ccccccc3", "fix: handle nil"),
+		entry("ddddddd4", "fix: handle empty"),
+		entry("eeeeeee5", "docs: update readme"),
+	})
+	require.Equal(t, BumpMajor, r.Bump)
+	require.Equal(t, "v2.0.0", r.Next.String())
+	require.Equal(t, []string{
+		"breaking change: api: drop v1 endpoints (aaaaaaa)",
+		"also 1 feature, 2 fixes and 1 other commit",
+	}, r.Reasons)
+
+	r = Recommend(current, []changelog.Entry{entry("bbbbbbb2", "feat: add dark mode"), entry("ccccccc3", "fix: handle nil")})
+	require.Equal(t, "v1.5.0", r.Next.String())
+	require.Equal(t, []string{"feature: add dark mode (bbbbbbb)", "also 1 fix"}, r.Reasons)
+
+	r = Recommend(current, []changelog.Entry{entry("ccccccc3", "perf: cache diffs")})
+	require.Equal(t, "v1.4.3", r.Next.String())
+
+	r = Recommend(current, []changelog.Entry{entry("eeeeeee5", "Update readme"), entry("fffffff6", "chore: tidy")})
+	require.Equal(t, BumpPatch, r.Bump)
+	require.Equal(t, []string{"no features or fixes, only 2 other commits"}, r.Reasons)
+}
+
+func TestRecommendBeforeOne(t *testing.T) {
+	r := Recommend(Version{Minor: 3, Patch: 1}, []changelog.Entry{
+		changelog.NewEntry("aaaaaaa1", "refactor: rename config\n\nBREAKING CHANGE: keys were renamed"),
+	})
+	require.Equal(t, BumpMinor, r.Bump)
+	require.Equal(t, "0.4.0", r.Next.String())
+	require.Contains(t, r.Reasons, "breaking changes bump the minor version before 1.0.0")
+}
@@ -0,0 +1,163 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+)
+
+// SeverityNone disables the gate
+const SeverityNone = "none"
+
+// Rank orders severities, returning -1 for unknown ones
+func Rank(severity string) int {
+	for i, s := range llm.Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// Blocking returns the findings at or above threshold. Nothing blocks when
+// the threshold is empty or SeverityNone.
+func Blocking(findings []llm.Finding, threshold string) []llm.Finding {
+	if threshold == "" || threshold == SeverityNone {
+		return nil
+	}
+
+	min := Rank(threshold)
+	var blocking []llm.Finding
+	for _, f := range findings {
+		if Rank(f.Severity) >= min {
+			blocking = append(blocking, f)
+		}
+	}
+	return blocking
+}
+
+// Sort orders findings by file and line, most severe first on the same line
+func Sort(findings []llm.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return Rank(a.Severity) > Rank(b.Severity)
+	})
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "high", "critical":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF formats findings as a SARIF 2.1.0 log, with one rule per category
+func SARIF(findings []llm.Finding, version string) ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "zeus-ai",
+		Version:        version,
+		InformationURI: "https://github.com/amosehiguese/zeus-ai",
+		Rules:          []sarifRule{},
+	}
+	seen := map[string]bool{}
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		if !seen[f.Category] {
+			seen[f.Category] = true
+			driver.Rules = append(driver.Rules, sarifRule{ID: f.Category, ShortDescription: sarifMessage{Text: f.Category + " issue"}})
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.File}}
+		// SARIF lines start at 1, so file-level findings have no region
+		if f.Line > 0 {
+			location.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:     f.Category,
+			Level:      sarifLevel(f.Severity),
+			Message:    sarifMessage{Text: f.Message},
+			Locations:  []sarifLocation{{PhysicalLocation: location}},
+			Properties: map[string]string{"severity": f.Severity},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format SARIF: %w", err)
+	}
+	return data, nil
+}
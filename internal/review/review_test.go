@@ -0,0 +1,62 @@
+package review
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+)
+
+var findings = []llm.Finding{
+	{File: "b.go", Line: 3, Severity: "low", Category: "style", Message: "rename x"},
+	{File: "a.go", Line: 10, Severity: "medium", Category: "bug", Message: "nil check"},
+	{File: "a.go", Line: 10, Severity: "critical", Category: "security", Message: "SQL injection"},
+	{File: "a.go", Line: 0, Severity: "info", Category: "bug", Message: "file note"},
+}
+
+func TestSortAndBlocking(t *testing.T) {
+	sorted := append([]llm.Finding(nil), findings...)
+	Sort(sorted)
+	require.Equal(t, []string{"file note", "SQL injection", "nil check", "rename x"}, messages(sorted))
+
+	require.Equal(t, []string{"nil check", "SQL injection"}, messages(Blocking(findings, "medium")), "The threshold itself blocks")
+	require.Equal(t, []string{"SQL injection"}, messages(Blocking(findings, "critical")))
+	require.Empty(t, Blocking(findings, SeverityNone))
+	require.Empty(t, Blocking(findings, ""))
+}
+
+func messages(findings []llm.Finding) []string {
+	var m []string
+	for _, f := range findings {
+		m = append(m, f.Message)
+	}
+	return m
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := SARIF(findings, "1.0.0")
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Equal(t, "zeus-ai", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 3, "One rule per category")
+	require.Len(t, run.Results, 4)
+
+	require.Equal(t, "note", run.Results[0].Level)
+	require.Equal(t, "warning", run.Results[1].Level)
+	require.Equal(t, "error", run.Results[2].Level)
+	require.Equal(t, "security", run.Results[2].RuleID)
+	require.Equal(t, 10, run.Results[2].Locations[0].PhysicalLocation.Region.StartLine)
+	require.Nil(t, run.Results[3].Locations[0].PhysicalLocation.Region, "File-level findings have no region")
+
+	data, err = SARIF(nil, "1.0.0")
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"results": []`)
+}
@@ -0,0 +1,285 @@
+package scope
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/conventional"
+)
+
+// Rule maps the paths matching a glob to a scope
+type Rule struct {
+	Glob  string
+	Scope string
+	re    *regexp.Regexp
+}
+
+// ParseRule reads a "glob=scope" rule. In the glob, "**" matches any number
+// of directories, "*" and "?" match within a path element, and a glob that
+// matches a directory matches everything below it.
+func ParseRule(s string) (Rule, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 0 {
+		return Rule{}, fmt.Errorf("invalid scope rule %q: expected glob=scope", s)
+	}
+
+	r := Rule{Glob: strings.Trim(strings.TrimSpace(s[:i]), "/"), Scope: strings.TrimSpace(s[i+1:])}
+	if r.Glob == "" || r.Scope == "" {
+		return Rule{}, fmt.Errorf("invalid scope rule %q: expected glob=scope", s)
+	}
+
+	re, err := regexp.Compile("^" + globPattern(r.Glob) + "$")
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid scope rule %q: %w", s, err)
+	}
+	r.re = re
+	return r, nil
+}
+
+// ParseRules reads a list of "glob=scope" rules
+func ParseRules(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, len(rules))
+	for i, s := range rules {
+		r, err := ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = r
+	}
+	return parsed, nil
+}
+
+func globPattern(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether file, relative to the repository root, or one of its
+// parent directories matches the rule
+func (r Rule) Match(file string) bool {
+	for p := file; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if r.re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// manifests are the files that mark the root of a module
+var manifests = []string{"go.mod", "package.json", "Cargo.toml"}
+
+// skipped are directories whose modules are never scopes
+var skipped = map[string]bool{"vendor": true, "node_modules": true, "testdata": true}
+
+// Infer returns the scopes of files, relative to the repository root, most
+// used first. The first matching rule gives the scope of a file. Without one
+// and with detect set, the nearest module below the root does: its name is
+// read from go.mod, package.json or Cargo.toml.
+func Infer(root string, files []string, rules []Rule, detect bool) []string {
+	counts := map[string]int{}
+	modules := map[string]string{}
+	for _, file := range files {
+		if s := fileScope(root, file, rules, detect, modules); s != "" {
+			counts[s]++
+		}
+	}
+
+	scopes := make([]string, 0, len(counts))
+	for s := range counts {
+		scopes = append(scopes, s)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if counts[scopes[i]] != counts[scopes[j]] {
+			return counts[scopes[i]] > counts[scopes[j]]
+		}
+		return scopes[i] < scopes[j]
+	})
+	return scopes
+}
+
+// fileScope returns the scope of file, caching the module names of
+// directories in modules
+func fileScope(root, file string, rules []Rule, detect bool, modules map[string]string) string {
+	for _, r := range rules {
+		if r.Match(file) {
+			return r.Scope
+		}
+	}
+	if !detect {
+		return ""
+	}
+
+	for _, part := range strings.Split(file, "/") {
+		if skipped[part] {
+			return ""
+		}
+	}
+
+	for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		name, ok := modules[dir]
+		if !ok {
+			name = moduleName(filepath.Join(root, filepath.FromSlash(dir)))
+			modules[dir] = name
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// moduleName returns the name of the module rooted at dir, or an empty string
+// when dir has no manifest
+func moduleName(dir string) string {
+	for _, manifest := range manifests {
+		data, err := os.ReadFile(filepath.Join(dir, manifest))
+		if err != nil {
+			continue
+		}
+
+		var name string
+		switch manifest {
+		case "go.mod":
+			name = goModuleName(string(data))
+		case "package.json":
+			name = packageName(data)
+		case "Cargo.toml":
+			name = crateName(string(data))
+		}
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		return name
+	}
+	return ""
+}
+
+// majorVersion matches the /vN suffix of Go module paths
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+func goModuleName(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+
+		module := strings.Trim(fields[1], `"`)
+		name := path.Base(module)
+		if majorVersion.MatchString(name) && path.Dir(module) != "." {
+			name = path.Base(path.Dir(module))
+		}
+		return name
+	}
+	return ""
+}
+
+func packageName(data []byte) string {
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" {
+		return ""
+	}
+	// Scoped packages are named "@org/name"
+	return path.Base(pkg.Name)
+}
+
+func crateName(toml string) string {
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(toml))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if section != "[package]" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// Apply checks the scope of a conventional message against the inferred
+// scopes. A message without a scope gets the scope when there is only one.
+// Otherwise the message may leave the scope out or use any of the inferred
+// scopes, comma separated, and an error names the ones that are not inferred;
+// the message is then returned unchanged. Messages that are not conventional
+// are returned unchanged too.
+func Apply(message string, scopes []string) (string, error) {
+	if len(scopes) == 0 {
+		return message, nil
+	}
+	c, ok := conventional.Parse(message)
+	if !ok {
+		return message, nil
+	}
+
+	if c.Scope == "" {
+		if len(scopes) == 1 {
+			message, _ = conventional.WithScope(message, scopes[0])
+		}
+		return message, nil
+	}
+
+	var unknown []string
+	for _, part := range strings.Split(c.Scope, ",") {
+		if !contains(scopes, strings.TrimSpace(part)) {
+			unknown = append(unknown, strings.TrimSpace(part))
+		}
+	}
+	switch {
+	case len(unknown) == 0:
+		return message, nil
+	case len(scopes) == 1:
+		return message, fmt.Errorf("scope %q is not the inferred scope %s", strings.Join(unknown, ","), scopes[0])
+	default:
+		return message, fmt.Errorf("scope %q is not one of the inferred scopes %s", strings.Join(unknown, ","), strings.Join(scopes, ", "))
+	}
+}
+
+func contains(scopes []string, s string) bool {
+	for _, scope := range scopes {
+		if strings.EqualFold(scope, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hint describes the inferred scopes for the prompt
+func Hint(scopes []string) string {
+	if len(scopes) == 1 {
+		return fmt.Sprintf("The changed files belong to the %q scope; use it as the conventional commit scope", scopes[0])
+	}
+	return fmt.Sprintf("The changed files belong to the scopes %s, most changed first; use the main one as the conventional commit scope, or no scope when the change spans them evenly", strings.Join(scopes, ", "))
+}
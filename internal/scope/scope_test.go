@@ -0,0 +1,97 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleMatch(t *testing.T) {
+	for glob, cases := range map[string]map[string]bool{
+		"services/billing": {"services/billing/main.go": true, "services/billing2/main.go": false},
+		"services/*/api":   {"services/auth/api/v1/user.go": true, "services/auth/web/app.ts": false},
+		"**/*.proto":       {"api.proto": true, "proto/v1/user.proto": true, "proto/v1/user.go": false},
+		"libs/**/ui":       {"libs/ui/button.tsx": true, "libs/web/kit/ui/button.tsx": true},
+	} {
+		r, err := ParseRule(glob + "=x")
+		require.NoError(t, err)
+		for file, want := range cases {
+			require.Equal(t, want, r.Match(file), "%s against %s", file, glob)
+		}
+	}
+
+	for _, s := range []string{"services/billing", "=billing", "services/billing="} {
+		_, err := ParseRule(s)
+		require.Error(t, err, s)
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestInfer(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/mono\n")
+	writeFile(t, root, "services/billing/go.mod", "module example.com/mono/services/billing/v2\n\ngo 1.22\n")
+	writeFile(t, root, "web/package.json", `{"name": "@acme/dashboard"}`)
+	writeFile(t, root, "crates/parser/Cargo.toml", "[workspace]\nname = \"no\"\n\n[package]\nname = \"acme-parser\"\n")
+	writeFile(t, root, "tools/lint/package.json", `{}`)
+	writeFile(t, root, "services/billing/testdata/go.mod", "module fixture\n")
+
+	files := []string{
+		"services/billing/invoice.go",
+		"services/billing/internal/tax/tax.go",
+		"web/src/App.tsx",
+		"crates/parser/src/lib.rs",
+		"tools/lint/index.js",
+		"README.md",
+		"docs/guide.md",
+	}
+	require.Equal(t, []string{"billing", "acme-parser", "dashboard", "lint"}, Infer(root, files, nil, true),
+		"Scopes come from the nearest module below the root, most used first")
+
+	rules, err := ParseRules([]string{"docs=docs", "services/billing/internal/**=tax"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"billing", "docs", "tax"}, Infer(root, []string{"services/billing/invoice.go", "docs/guide.md", "services/billing/internal/tax/tax.go"}, rules, true),
+		"Rules take precedence over detected modules")
+
+	require.Empty(t, Infer(root, files, nil, false))
+	require.Empty(t, Infer(root, []string{"services/billing/testdata/x.go"}, nil, true), "Fixtures are not modules")
+}
+
+func TestApply(t *testing.T) {
+	msg, err := Apply("feat: add invoices\n\nBody.", []string{"billing"})
+	require.NoError(t, err)
+	require.Equal(t, "feat(billing): add invoices\n\nBody.", msg)
+
+	msg, err = Apply("fix(invoice)!: round totals", []string{"billing"})
+	require.EqualError(t, err, `scope "invoice" is not the inferred scope billing`)
+	require.Equal(t, "fix(invoice)!: round totals", msg, "A scope given in the message is kept")
+
+	msg, err = Apply("fix(api,db): round totals", []string{"billing"})
+	require.EqualError(t, err, `scope "api,db" is not the inferred scope billing`)
+	require.Equal(t, "fix(api,db): round totals", msg)
+
+	msg, err = Apply("fix(Billing): round totals", []string{"billing"})
+	require.NoError(t, err)
+	require.Equal(t, "fix(Billing): round totals", msg)
+
+	scopes := []string{"billing", "web"}
+	for _, ok := range []string{"feat: add invoices", "feat(web): add invoices", "feat(billing, web): add invoices", "Add invoices"} {
+		msg, err = Apply(ok, scopes)
+		require.NoError(t, err, ok)
+		require.Equal(t, ok, msg)
+	}
+
+	_, err = Apply("feat(api,web): add invoices", scopes)
+	require.EqualError(t, err, `scope "api" is not one of the inferred scopes billing, web`)
+
+	msg, err = Apply("feat(api): add invoices", nil)
+	require.NoError(t, err)
+	require.Equal(t, "feat(api): add invoices", msg)
+}
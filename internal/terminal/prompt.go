@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// stdin is shared by every prompt so that buffered input is not lost between them
+var stdin = bufio.NewReader(os.Stdin)
+
+// IsInteractive reports whether stdin is a terminal
+func IsInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// IsOutputTerminal reports whether stdout is a terminal
+func IsOutputTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Prompt asks for a line of input, returning def when the answer is empty
+func Prompt(label, def string) (string, error) {
+	if def != "" {
+		PromptColor.Printf("%s [%s]: ", label, def)
+	} else {
+		PromptColor.Printf("%s: ", label)
+	}
+
+	input, err := stdin.ReadString('\n')
+	if err != nil && input == "" {
+		return "", fmt.Errorf("input error: %w", err)
+	}
+
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return def, nil
+	}
+	return input, nil
+}
+
+// Select shows a numbered list of options and returns the index of the chosen
+// one. def is the index used when the answer is empty.
+func Select(label string, options []string, def int) (int, error) {
+	PromptColor.Println(label)
+	for i, option := range options {
+		marker := " "
+		if i == def {
+			marker = "*"
+		}
+		OptionColor.Printf("  %s %d. %s\n", marker, i+1, option)
+	}
+
+	for {
+		input, err := Prompt("  Select an option", strconv.Itoa(def+1))
+		if err != nil {
+			return -1, err
+		}
+
+		idx, err := strconv.Atoi(input)
+		if err == nil && idx >= 1 && idx <= len(options) {
+			return idx - 1, nil
+		}
+		ShowError(fmt.Sprintf("Invalid selection. Please choose 1-%d", len(options)))
+	}
+}
@@ -1,7 +1,6 @@
 package terminal
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,12 +9,25 @@ import (
 	"time"
 )
 
-func DisplayAndSelectSuggestion(suggestions []string) (int, error) {
+// Selections returned by DisplayAndSelectSuggestion besides a suggestion index
+const (
+	SelectEdit       = -1
+	SelectRegenerate = -2
+)
+
+// DisplayAndSelectSuggestion shows the suggestions and returns the index of
+// the chosen one, SelectEdit, or SelectRegenerate when cached is set and the
+// user asked for fresh suggestions.
+func DisplayAndSelectSuggestion(suggestions []string, cached bool) (int, error) {
 	if len(suggestions) != 3 {
 		return -1, fmt.Errorf("expected 3 suggestions, got %d", len(suggestions))
 	}
 
-	printHeader("COMMIT MESSAGE SUGGESTIONS")
+	if cached {
+		printHeader("COMMIT MESSAGE SUGGESTIONS (CACHED)")
+	} else {
+		printHeader("COMMIT MESSAGE SUGGESTIONS")
+	}
 
 	for i, suggestion := range suggestions {
 		parts := strings.SplitN(suggestion, "\n\n", 2)
@@ -26,8 +38,8 @@ func DisplayAndSelectSuggestion(suggestions []string) (int, error) {
 		}
 	}
 
-	printOptions()
-	return getSelection(len(suggestions))
+	printOptions(cached)
+	return getSelection(len(suggestions), cached)
 }
 
 func ShowDiff(diff string) {
@@ -77,8 +89,7 @@ func ShowSpinner(message string) func() {
 func Confirm(prompt string) (bool, error) {
 	PromptColor.Printf("%s (y/N): ", prompt)
 
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
+	input, err := stdin.ReadString('\n')
 	if err != nil {
 		return false, fmt.Errorf("input error: %w", err)
 	}
@@ -87,6 +98,33 @@ func Confirm(prompt string) (bool, error) {
 	return input == "y" || input == "yes", nil
 }
 
+// ReadSecret prompts for a value without echoing it when stdin is a terminal
+func ReadSecret(prompt string) (string, error) {
+	PromptColor.Printf("%s: ", prompt)
+
+	if IsInteractive() {
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				_ = stty("echo")
+				fmt.Println()
+			}()
+		}
+	}
+
+	input, err := stdin.ReadString('\n')
+	if err != nil && input == "" {
+		return "", fmt.Errorf("input error: %w", err)
+	}
+
+	return strings.TrimSpace(input), nil
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
 func ShowSuccess(message string) {
 	SuccessColor.Println("✓", message)
 }
@@ -106,18 +144,24 @@ func printHeader(title string) {
 	DividerColor.Println("├───────────────────────────────────────────────────────┤")
 }
 
-func printOptions() {
+func printOptions(regenerate bool) {
 	DividerColor.Println("├───────────────────────────────────────────────────────┤")
+	if regenerate {
+		OptionColor.Println("  r - Regenerate fresh suggestions")
+	}
 	OptionColor.Println("  e - Edit manually")
 	OptionColor.Println("  q - Quit without committing")
 	DividerColor.Println("└───────────────────────────────────────────────────────┘")
 }
 
-func getSelection(max int) (int, error) {
-	PromptColor.Print("\n  Select an option (1-3/e/q): ")
+func getSelection(max int, regenerate bool) (int, error) {
+	choices := "1-3/e/q"
+	if regenerate {
+		choices = "1-3/r/e/q"
+	}
+	PromptColor.Printf("\n  Select an option (%s): ", choices)
 
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
+	input, err := stdin.ReadString('\n')
 	if err != nil {
 		return -1, fmt.Errorf("input error: %w", err)
 	}
@@ -125,15 +169,19 @@ func getSelection(max int) (int, error) {
 	input = strings.TrimSpace(strings.ToLower(input))
 	switch input {
 	case "e":
-		return -1, nil // Edit mode
+		return SelectEdit, nil
+	case "r":
+		if regenerate {
+			return SelectRegenerate, nil
+		}
 	case "q":
 		os.Exit(0)
 	}
 
 	idx, err := strconv.Atoi(input)
 	if err != nil || idx < 1 || idx > max {
-		ShowError("Invalid selection. Please choose 1-3, e, or q")
-		return getSelection(max)
+		ShowError(fmt.Sprintf("Invalid selection. Please choose %s", choices))
+		return getSelection(max, regenerate)
 	}
 	return idx - 1, nil
 }
@@ -142,8 +190,8 @@ func indentBody(body string) string {
 	return strings.ReplaceAll(body, "\n", "\n     ")
 }
 
-// EditMessage opens the default editor to edit the message
-func EditMessage(initialContent string, includeBody bool) (string, error) {
+// EditMessage opens an editor to edit the message, see EditFile for how the editor is chosen
+func EditMessage(initialContent string, includeBody bool, editor string) (string, error) {
 	// Create a temporary file
 	tmpFile, err := os.CreateTemp("", "zeus-commit-msg-*.txt")
 	if err != nil {
@@ -174,13 +222,47 @@ func EditMessage(initialContent string, includeBody bool) (string, error) {
 		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 
+	if err = EditFile(tmpFile.Name(), editor); err != nil {
+		return "", err
+	}
+
+	// Read the updated content
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	// Remove comments and trailing empty lines
+	lines := strings.Split(string(content), "\n")
+	var result []string
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			result = append(result, line)
+		}
+	}
+
+	// Trim empty lines from the end
+	for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+		result = result[:len(result)-1]
+	}
+
+	return strings.Join(result, "\n"), nil
+}
+
+// EditFile opens path in an editor. editor may include arguments; when empty,
+// $EDITOR, $VISUAL and a list of common editors are tried.
+func EditFile(path string, editor string) error {
 	// Get editor from environment or use a default
-	editor := os.Getenv("EDITOR")
+	editor = strings.TrimSpace(editor)
 	if editor == "" {
-		editor = os.Getenv("VISUAL")
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("VISUAL"))
 	}
 	if editor == "" {
 		// Try to find a default editor based on the OS
+		var err error
 		if _, err = exec.LookPath("nano"); err == nil {
 			editor = "nano"
 		} else if _, err = exec.LookPath("vim"); err == nil {
@@ -190,40 +272,21 @@ func EditMessage(initialContent string, includeBody bool) (string, error) {
 		} else if _, err = exec.LookPath("notepad"); err == nil {
 			editor = "notepad"
 		} else {
-			return "", fmt.Errorf("no suitable editor found, please set EDITOR environment variable")
+			return fmt.Errorf("no suitable editor found, please set EDITOR environment variable")
 		}
 	}
 
 	// Open the editor
-	cmd := exec.Command(editor, tmpFile.Name())
+	editorArgs := strings.Fields(editor)
+	editorArgs = append(editorArgs, path)
+	cmd := exec.Command(editorArgs[0], editorArgs[1:]...) // #nosec G204 -- the editor is chosen by the user
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	err = cmd.Run()
-	if err != nil {
-		return "", fmt.Errorf("editor command failed: %w", err)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor command failed: %w", err)
 	}
 
-	// Read the updated content
-	content, err := os.ReadFile(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	// Remove comments and trailing empty lines
-	lines := strings.Split(string(content), "\n")
-	var result []string
-	for _, line := range lines {
-		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
-			result = append(result, line)
-		}
-	}
-
-	// Trim empty lines from the end
-	for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
-		result = result[:len(result)-1]
-	}
-
-	return strings.Join(result, "\n"), nil
+	return nil
 }
@@ -0,0 +1,90 @@
+package ticket
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/conventional"
+)
+
+// Extract returns the ticket ID in branch found by the first matching
+// pattern, or an empty string when none matches. The ID is the pattern's
+// "ticket" group, its first group or else the whole match.
+func Extract(branch string, patterns []string) (string, error) {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid ticket pattern %q: %w", pattern, err)
+		}
+
+		m := re.FindStringSubmatch(branch)
+		if m == nil {
+			continue
+		}
+		if i := re.SubexpIndex("ticket"); i > 0 && m[i] != "" {
+			return m[i], nil
+		}
+		if len(m) > 1 && m[1] != "" {
+			return m[1], nil
+		}
+		return m[0], nil
+	}
+
+	return "", nil
+}
+
+// Required reports whether branch matches one of the globs of branches that
+// must reference a ticket
+func Required(branch string, globs []string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix starts the title of message with "[id] " unless the title already
+// mentions the ID
+func Prefix(message, id string) string {
+	title, rest, _ := strings.Cut(message, "\n")
+	if strings.Contains(title, id) {
+		return message
+	}
+	return join("["+id+"] "+title, rest)
+}
+
+// Scope puts the ID in the scope of a conventional commit title, after any
+// existing scope. Titles that are not conventional get the ID as a prefix.
+func Scope(message, id string) string {
+	title, _, _ := strings.Cut(message, "\n")
+	if strings.Contains(title, id) {
+		return message
+	}
+
+	c, ok := conventional.Parse(message)
+	if !ok {
+		return Prefix(message, id)
+	}
+
+	scope := id
+	if c.Scope != "" {
+		scope = c.Scope + "," + id
+	}
+	message, _ = conventional.WithScope(message, scope)
+	return message
+}
+
+// Hint describes the ticket for the prompt
+func Hint(id string) string {
+	return fmt.Sprintf("The changes belong to ticket %s, taken from the branch name", id)
+}
+
+func join(title, rest string) string {
+	if rest == "" {
+		return title
+	}
+	return title + "\n" + rest
+}
@@ -0,0 +1,49 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	patterns := []string{`(?P<ticket>[A-Z][A-Z0-9]+-\d+)`, `^issue-(\d+)`}
+
+	id, err := Extract("feature/PAY-1234-refund-flow", patterns)
+	require.NoError(t, err)
+	require.Equal(t, "PAY-1234", id)
+
+	id, err = Extract("issue-42-typo", patterns)
+	require.NoError(t, err)
+	require.Equal(t, "42", id, "The first group is used without a ticket group")
+
+	id, err = Extract("release/v1.2", []string{`v\d+`})
+	require.NoError(t, err)
+	require.Equal(t, "v1", id, "The whole match is used without groups")
+
+	id, err = Extract("main", patterns)
+	require.NoError(t, err)
+	require.Empty(t, id)
+
+	_, err = Extract("main", []string{"("})
+	require.Error(t, err)
+}
+
+func TestRequired(t *testing.T) {
+	globs := []string{"feature/*", "fix/*"}
+	require.True(t, Required("feature/refund-flow", globs))
+	require.False(t, Required("feature/a/b", globs), "Globs do not cross slashes")
+	require.False(t, Required("main", globs))
+}
+
+func TestPrefix(t *testing.T) {
+	require.Equal(t, "[PAY-1] feat: add refunds\n\nBody.", Prefix("feat: add refunds\n\nBody.", "PAY-1"))
+	require.Equal(t, "PAY-1: add refunds", Prefix("PAY-1: add refunds", "PAY-1"), "Titles mentioning the ticket are kept")
+}
+
+func TestScope(t *testing.T) {
+	require.Equal(t, "feat(PAY-1): add refunds", Scope("feat: add refunds", "PAY-1"))
+	require.Equal(t, "fix(api,PAY-1)!: drop v1\n\nBody.", Scope("fix(api)!: drop v1\n\nBody.", "PAY-1"))
+	require.Equal(t, "[PAY-1] Add refunds", Scope("Add refunds", "PAY-1"), "Other titles get a prefix")
+	require.Equal(t, "feat(PAY-1): add refunds", Scope("feat(PAY-1): add refunds", "PAY-1"))
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/apidiff"
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/conventional"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+// maxAPIHints caps the API changes listed in the prompt
+const maxAPIHints = 20
+
+// fileReader reads a file, relative to the repository root, on one side of a diff
+type fileReader func(path string) ([]byte, error)
+
+// atRevision reads files at a commit, or from the index when rev is empty
+func atRevision(rev string) fileReader {
+	return func(path string) ([]byte, error) {
+		return git.ShowFile(rev, path)
+	}
+}
+
+// inWorkTree reads files from the working tree
+func inWorkTree() fileReader {
+	return func(path string) ([]byte, error) {
+		root, err := git.RepoRoot()
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+	}
+}
+
+// apiChanges compares the exported Go API of the files diff touches. The
+// analysis is best effort: files that cannot be read are skipped.
+func apiChanges(diff string, before, after fileReader) []apidiff.Change {
+	var files []apidiff.File
+	for _, f := range git.DiffFiles(diff) {
+		if !strings.HasSuffix(f.OldPath, ".go") && !strings.HasSuffix(f.NewPath, ".go") {
+			continue
+		}
+
+		file := apidiff.File{Path: f.NewPath}
+		if file.Path == "" {
+			file.Path = f.OldPath
+		}
+		var err error
+		if f.OldPath != "" {
+			if file.Old, err = before(f.OldPath); err != nil {
+				continue
+			}
+		}
+		if f.NewPath != "" {
+			if file.New, err = after(f.NewPath); err != nil {
+				continue
+			}
+		}
+		files = append(files, file)
+	}
+	return apidiff.Compare(files)
+}
+
+// apiHints returns the prompt hints listing the API changes, asking for
+// breaking changes to be marked in conventional style
+func apiHints(changes []apidiff.Change, style string) []string {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var section strings.Builder
+	section.WriteString("API changes found by comparing the exported Go declarations before and after the change:")
+	for i, c := range changes {
+		if i == maxAPIHints {
+			fmt.Fprintf(&section, "\n  - and %d more", len(changes)-i)
+			break
+		}
+		fmt.Fprintf(&section, "\n  - %s", c)
+	}
+	hints := []string{section.String()}
+
+	if apidiff.HasBreaking(changes) && style == "conventional" {
+		hints = append(hints, `The change breaks the exported API: mark the title with "!" after the type or scope, as in "feat(api)!: description", and describe the break in a "BREAKING CHANGE:" footer when a body is included`)
+	}
+	return hints
+}
+
+// markBreaking makes sure a conventional message for a change that breaks
+// the API says so, whatever the model or the editor produced
+func markBreaking(message string, changes []apidiff.Change, cfg *config.Config) string {
+	if cfg.DefaultStyle != "conventional" || !apidiff.HasBreaking(changes) {
+		return message
+	}
+
+	marked, ok := conventional.MarkBreaking(message)
+	if !ok {
+		terminal.ShowWarning("The change breaks the exported Go API, but the message is not a conventional commit and is not marked as breaking")
+	}
+	return marked
+}
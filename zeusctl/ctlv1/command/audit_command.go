@@ -0,0 +1,140 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	auditLimitFlag     int
+	auditOlderThanFlag time.Duration
+	auditAllFlag       bool
+)
+
+func NewAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of provider requests",
+		Long: `Inspect the audit log of everything sent to and received from LLM providers.
+Auditing is opt-in: set "audit: true" in .zeusrc or ZEUS_AUDIT=true to enable it.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded audit entries",
+		Args:  cobra.NoArgs,
+		RunE:  auditListCommandFunc,
+	}
+	listCmd.Flags().IntVar(&auditLimitFlag, "limit", 20, "Maximum number of entries to show (0 for all)")
+
+	showCmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a single audit entry including prompt and response",
+		Args:  cobra.ExactArgs(1),
+		RunE:  auditShowCommandFunc,
+	}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old audit entries",
+		Args:  cobra.NoArgs,
+		RunE:  auditPruneCommandFunc,
+	}
+	pruneCmd.Flags().DurationVar(&auditOlderThanFlag, "older-than", 30*24*time.Hour, "Remove entries older than this duration")
+	pruneCmd.Flags().BoolVar(&auditAllFlag, "all", false, "Remove every entry")
+
+	cmd.AddCommand(listCmd, showCmd, pruneCmd)
+	return cmd
+}
+
+func auditListCommandFunc(cmd *cobra.Command, args []string) error {
+	auditLog, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	entries, err := auditLog.Entries()
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if len(entries) == 0 {
+		terminal.ShowWarning("No audit entries recorded")
+		return nil
+	}
+
+	// Show the most recent entries first
+	if auditLimitFlag > 0 && len(entries) > auditLimitFlag {
+		entries = entries[len(entries)-auditLimitFlag:]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tPROVIDER\tMODEL\tTOKENS\tLATENCY\tREPO")
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		status := ""
+		if e.Error != "" {
+			status = " (failed)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%dms%s\t%s\n",
+			e.ID,
+			e.Timestamp.Local().Format(time.DateTime),
+			e.Provider,
+			e.Model,
+			e.Usage.PromptTokens,
+			e.Usage.CompletionTokens,
+			e.LatencyMS,
+			status,
+			e.Repo,
+		)
+	}
+
+	return w.Flush()
+}
+
+func auditShowCommandFunc(cmd *cobra.Command, args []string) error {
+	auditLog, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	entry, err := auditLog.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format audit entry: %w", err)
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
+func auditPruneCommandFunc(cmd *cobra.Command, args []string) error {
+	auditLog, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-auditOlderThanFlag)
+	if auditAllFlag {
+		before = time.Now().Add(time.Hour)
+	}
+
+	removed, err := auditLog.Prune(before)
+	if err != nil {
+		return fmt.Errorf("failed to prune audit log: %w", err)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Removed %d audit entries", removed))
+	return nil
+}
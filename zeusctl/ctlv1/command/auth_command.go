@@ -0,0 +1,165 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/credential"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	authProviderFlag string
+	authStdinFlag    bool
+)
+
+func NewAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage provider API keys in the OS keyring",
+		Long: `Manage provider API keys in the OS keyring so they never need to be written to .zeusrc.
+
+Keys are looked up in this order: api_key (flag, ZEUS_API_KEY or config),
+api_key_cmd, api_key_file and finally the keyring.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&authProviderFlag, "provider", "", "Provider to manage (defaults to the configured provider)")
+
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store an API key in the OS keyring",
+		Args:  cobra.NoArgs,
+		RunE:  authLoginCommandFunc,
+	}
+	loginCmd.Flags().BoolVar(&authStdinFlag, "stdin", false, "Read the key from standard input")
+
+	cmd.AddCommand(
+		loginCmd,
+		&cobra.Command{
+			Use:   "logout",
+			Short: "Remove the API key from the OS keyring",
+			Args:  cobra.NoArgs,
+			RunE:  authLogoutCommandFunc,
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show where the API key is resolved from",
+			Args:  cobra.NoArgs,
+			RunE:  authStatusCommandFunc,
+		},
+	)
+
+	return cmd
+}
+
+func authProvider() (*config.Config, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	provider := authProviderFlag
+	if provider == "" {
+		provider = cfg.Provider
+	}
+	return cfg, strings.ToLower(provider), nil
+}
+
+func authLoginCommandFunc(cmd *cobra.Command, args []string) error {
+	_, provider, err := authProvider()
+	if err != nil {
+		return err
+	}
+
+	store, err := credential.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	var key string
+	if authStdinFlag {
+		key, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && key == "" {
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+		key = strings.TrimSpace(key)
+	} else {
+		key, err = terminal.ReadSecret(fmt.Sprintf("API key for %s", provider))
+		if err != nil {
+			return err
+		}
+	}
+	if key == "" {
+		return errors.New("no API key given")
+	}
+
+	if err = store.Set(provider, key); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Stored %s API key in %s", provider, store.Name()))
+	return nil
+}
+
+func authLogoutCommandFunc(cmd *cobra.Command, args []string) error {
+	_, provider, err := authProvider()
+	if err != nil {
+		return err
+	}
+
+	store, err := credential.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	err = store.Delete(provider)
+	if errors.Is(err, credential.ErrNotFound) {
+		terminal.ShowWarning(fmt.Sprintf("No %s API key stored in %s", provider, store.Name()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Removed %s API key from %s", provider, store.Name()))
+	return nil
+}
+
+func authStatusCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, provider, err := authProvider()
+	if err != nil {
+		return err
+	}
+	cfg.Provider = provider
+
+	store, storeErr := credential.DefaultStore()
+	_, source, err := credential.Resolve(cfg, store)
+	if err != nil {
+		return err
+	}
+
+	switch source {
+	case credential.SourceNone:
+		terminal.ShowWarning(fmt.Sprintf("No API key found for %s", provider))
+		if storeErr != nil {
+			terminal.ShowWarning(storeErr.Error())
+		}
+	case credential.SourceConfig:
+		terminal.ShowSuccess(fmt.Sprintf("%s API key from api_key (%s)", provider, cfg.Origin("api_key")))
+		if strings.HasPrefix(cfg.Origin("api_key"), "file:") {
+			terminal.ShowWarning("The key is stored in plaintext; consider `zeusctl auth login` instead")
+		}
+	case credential.SourceKeyring:
+		terminal.ShowSuccess(fmt.Sprintf("%s API key from %s", provider, store.Name()))
+	default:
+		terminal.ShowSuccess(fmt.Sprintf("%s API key from %s", provider, source))
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+	"github.com/amosehiguese/zeus-ai/internal/ticket"
+)
+
+var branchTicketFlag string
+
+func NewBranchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "branch [description]",
+		Short: "Suggest a branch name for the work in progress and switch to it",
+		Long: `Suggest branch names from the staged and unstaged changes or, when given,
+from a free-text description of the planned work. Names follow the
+branch_pattern setting, which may use {type}, {ticket} and {slug}, and are
+validated with git check-ref-format.
+
+The chosen branch is created at HEAD and checked out, carrying the working
+changes over.`,
+		RunE: branchCommandFunc,
+	}
+
+	// Flags override the matching config keys only when set, as for suggest
+	cmd.Flags().String("pattern", "{type}/{slug}", "Branch name pattern using {type}, {ticket} and {slug}")
+	cmd.Flags().Bool("dry-run", false, "Show the suggested names but don't create a branch")
+	cmd.Flags().StringVar(&branchTicketFlag, "ticket", "", "Ticket ID for the {ticket} placeholder")
+
+	return cmd
+}
+
+func branchCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	description := strings.Join(args, " ")
+	input, fromDiff := description, false
+	if description == "" {
+		if input, err = workingDiff(); err != nil {
+			return err
+		}
+		if input == "" {
+			return fmt.Errorf("no changes found; describe the planned work instead, e.g. `zeusctl branch add refund flow`")
+		}
+		fromDiff = true
+	}
+
+	// The ticket can also be part of the description, as in "PAY-1234 refunds"
+	ticketID := branchTicketFlag
+	if ticketID == "" && description != "" {
+		if ticketID, err = ticket.Extract(description, cfg.Tickets.Patterns); err != nil {
+			return err
+		}
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	stopSpinner := terminal.ShowSpinner("Generating branch name suggestions...")
+	suggestions, res, err := llm.SuggestBranchNames(provider, input, fromDiff, ticketHints(ticketID))
+	stopSpinner()
+	if err != nil {
+		return fmt.Errorf("failed to suggest branch names: %w", err)
+	}
+	showUsage(res.Stats)
+
+	names := branchNames(suggestions, cfg.BranchPattern, ticketID)
+	if len(names) == 0 {
+		return fmt.Errorf("none of the suggested names are valid branch names for pattern %q", cfg.BranchPattern)
+	}
+
+	if cfg.DryRun {
+		terminal.TitleColor.Println("Suggested branch names:")
+		for i, name := range names {
+			terminal.OptionColor.Printf("  %d. %s\n", i+1, name)
+		}
+		return nil
+	}
+
+	name, err := selectBranchName(names)
+	if err != nil {
+		return err
+	}
+
+	exists, err := git.BranchExists(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("branch %q already exists", name)
+	}
+
+	if err = git.CreateBranch(name); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Switched to new branch %s", name))
+	return nil
+}
+
+// workingDiff returns the staged changes followed by the unstaged ones
+func workingDiff() (string, error) {
+	staged, err := git.GetDiff(true)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	unstaged, err := git.GetDiff(false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	return strings.TrimSpace(staged + "\n" + unstaged), nil
+}
+
+// branchNames formats the suggestions with pattern, dropping duplicates and
+// names git would reject
+func branchNames(suggestions []llm.BranchName, pattern, ticketID string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, s := range suggestions {
+		name := s.Format(pattern, ticketID)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if err := git.CheckBranchName(name); err != nil {
+			terminal.ShowWarning(fmt.Sprintf("Skipping suggestion: %v", err))
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// selectBranchName lets the user pick one of names or type their own
+func selectBranchName(names []string) (string, error) {
+	options := append(append([]string(nil), names...), "Enter a name", "Quit without creating a branch")
+	selected, err := terminal.Select("Create and switch to branch:", options, 0)
+	if err != nil {
+		return "", err
+	}
+
+	switch selected {
+	case len(names):
+		for {
+			name, promptErr := terminal.Prompt("Branch name", "")
+			if promptErr != nil {
+				return "", promptErr
+			}
+			if err = git.CheckBranchName(name); err == nil {
+				return name, nil
+			}
+			terminal.ShowError(err.Error())
+		}
+	case len(names) + 1:
+		return "", errors.New("no branch was created")
+	default:
+		return names[selected], nil
+	}
+}
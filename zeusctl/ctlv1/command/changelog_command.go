@@ -0,0 +1,175 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/changelog"
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	changelogFormatFlag string
+	changelogTitleFlag  string
+	changelogAllFlag    bool
+	changelogPolishFlag bool
+	changelogOutputFlag string
+)
+
+func NewChangelogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog <from>..<to>",
+		Short: "Generate a changelog for a range of commits",
+		Long: `Generate a changelog from the Conventional Commits messages in a range, such
+as v1.2.0..v1.3.0. A single revision is taken as the start of a range ending
+at HEAD.
+
+Changes are grouped by type, breaking changes are listed first, and entries
+are grouped by scope within each section. Only features, fixes, performance
+improvements and reverts are listed unless --all is given. With --polish, the
+LLM rewrites the entries as release notes and adds highlights.`,
+		Args: cobra.ExactArgs(1),
+		RunE: changelogCommandFunc,
+	}
+
+	cmd.Flags().StringVar(&changelogFormatFlag, "format", changelog.FormatMarkdown, "Output format ("+strings.Join(changelog.Formats, ", ")+")")
+	cmd.Flags().StringVar(&changelogTitleFlag, "title", "", "Release title (default: the end of the range, or Unreleased when it is HEAD)")
+	cmd.Flags().BoolVar(&changelogAllFlag, "all", false, "Include docs, tests, chores and commits that are not conventional")
+	cmd.Flags().BoolVar(&changelogPolishFlag, "polish", false, "Rewrite the entries as release notes with the LLM")
+	cmd.Flags().StringVarP(&changelogOutputFlag, "output", "o", "", "Write the changelog to a file instead of stdout")
+
+	return cmd
+}
+
+func changelogCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	from, to := parseRange(args[0])
+	entries, err := git.Log(from, to)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no commits in %s..%s", from, to)
+	}
+
+	// The release date is that of the end of the range, unless it is still
+	// unreleased
+	date := time.Now()
+	if to != "HEAD" {
+		if date, err = git.ReleaseDate(to); err != nil {
+			return err
+		}
+	}
+
+	log := changelog.Changelog{
+		Title: changelogTitleFlag,
+		Date:  date,
+		All:   changelogAllFlag,
+	}
+	if log.Title == "" {
+		log.Title = to
+		if to == "HEAD" {
+			log.Title = "Unreleased"
+		}
+	}
+	for _, e := range entries {
+		log.Entries = append(log.Entries, changelog.NewEntry(e.Commit, e.Message()))
+	}
+
+	var stats *llm.Stats
+	if changelogPolishFlag {
+		if stats, err = polishChangelog(cfg, &log); err != nil {
+			return err
+		}
+	}
+
+	out, err := log.Render(changelogFormatFlag)
+	if err != nil {
+		return err
+	}
+
+	if changelogOutputFlag == "" {
+		fmt.Fprint(os.Stdout, out)
+		if stats != nil {
+			// Usage goes to stderr so that stdout holds only the changelog
+			terminal.BodyColor.Fprintf(os.Stderr, "  %s\n", usageSummary(*stats))
+		}
+		return nil
+	}
+
+	if stats != nil {
+		showUsage(*stats)
+	}
+	if err = os.WriteFile(changelogOutputFlag, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	terminal.ShowSuccess(fmt.Sprintf("Wrote the changelog to %s", changelogOutputFlag))
+	return nil
+}
+
+// polishChangelog replaces the descriptions of the entries with the LLM's
+// rewrite and sets the highlights
+func polishChangelog(cfg *config.Config, log *changelog.Changelog) (*llm.Stats, error) {
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	var notes []llm.ChangeNote
+	for _, e := range log.Entries {
+		if !log.Listed(e) {
+			continue
+		}
+		notes = append(notes, llm.ChangeNote{ID: e.Hash, Type: e.Type, Text: e.Description})
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+
+	// The spinner would end up in the changelog when stdout is redirected
+	stopSpinner := func() {}
+	if changelogOutputFlag != "" || terminal.IsOutputTerminal() {
+		stopSpinner = terminal.ShowSpinner(fmt.Sprintf("Polishing %d changelog entries...", len(notes)))
+	}
+	polished, res, err := llm.PolishChangelog(provider, notes)
+	stopSpinner()
+	if err != nil {
+		return nil, fmt.Errorf("failed to polish changelog: %w", err)
+	}
+
+	log.Highlights = polished.Highlights
+	for i, e := range log.Entries {
+		if text, ok := polished.Texts[e.Hash]; ok {
+			log.Entries[i].Description = text
+		}
+	}
+	return &res.Stats, nil
+}
+
+// parseRange splits a "from..to" range, taking a single revision as the start
+// of a range that ends at HEAD
+func parseRange(arg string) (string, string) {
+	from, to, ok := strings.Cut(arg, "..")
+	if !ok || to == "" {
+		to = "HEAD"
+	}
+	return from, to
+}
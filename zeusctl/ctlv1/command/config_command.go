@@ -0,0 +1,285 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	configGlobalFlag     bool
+	configLocalFlag      bool
+	configShowOriginFlag bool
+)
+
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get and set zeus-ai configuration",
+		Long: `Get and set zeus-ai configuration, similar to git config.
+
+--global targets ~/.zeusrc and --local targets the repository .zeusrc.
+get and list show the effective configuration unless a scope is given;
+set, unset and edit default to --local.`,
+	}
+
+	cmd.PersistentFlags().BoolVar(&configGlobalFlag, "global", false, "Use the user-level config file (~/.zeusrc)")
+	cmd.PersistentFlags().BoolVar(&configLocalFlag, "local", false, "Use the repository config file (.zeusrc)")
+	cmd.MarkFlagsMutuallyExclusive("global", "local")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List configuration values",
+		Args:  cobra.NoArgs,
+		RunE:  configListCommandFunc,
+	}
+	listCmd.Flags().BoolVar(&configShowOriginFlag, "show-origin", false, "Show where each value comes from")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "get <key>",
+			Short: "Print a configuration value",
+			Args:  cobra.ExactArgs(1),
+			RunE:  configGetCommandFunc,
+		},
+		&cobra.Command{
+			Use:   "set <key> <value>",
+			Short: "Set a configuration value",
+			Args:  cobra.ExactArgs(2),
+			RunE:  configSetCommandFunc,
+		},
+		&cobra.Command{
+			Use:   "unset <key>",
+			Short: "Remove a configuration value",
+			Args:  cobra.ExactArgs(1),
+			RunE:  configUnsetCommandFunc,
+		},
+		listCmd,
+		&cobra.Command{
+			Use:   "edit",
+			Short: "Open the config file in an editor",
+			Args:  cobra.NoArgs,
+			RunE:  configEditCommandFunc,
+		},
+		&cobra.Command{
+			Use:   "path",
+			Short: "Print the config file locations",
+			Args:  cobra.NoArgs,
+			RunE:  configPathCommandFunc,
+		},
+	)
+
+	return cmd
+}
+
+func configGetCommandFunc(cmd *cobra.Command, args []string) error {
+	setting, err := lookupSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	if configGlobalFlag || configLocalFlag {
+		path, err := scopedConfigFile()
+		if err != nil {
+			return err
+		}
+		value, ok, err := config.GetFileValue(path, setting.Key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s is not set in %s", setting.Key, path)
+		}
+		fmt.Println(value)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	fmt.Println(setting.Get(cfg))
+	return nil
+}
+
+func configSetCommandFunc(cmd *cobra.Command, args []string) error {
+	setting, err := lookupSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Validate the value before writing it
+	if err = setting.Set(&config.Config{}, args[1]); err != nil {
+		return err
+	}
+
+	if setting.UserOnly && !configGlobalFlag {
+		return fmt.Errorf("%s can only be set in ~/.zeusrc; use --global", setting.Key)
+	}
+
+	path, err := scopedConfigFile()
+	if err != nil {
+		return err
+	}
+	if setting.Key == "api_key" && !configGlobalFlag {
+		if err = checkSecretFile(path); err != nil {
+			return err
+		}
+	}
+	if err = config.SetFileValue(path, setting.Key, args[1]); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Set %s in %s", setting.Key, path))
+	return nil
+}
+
+func configUnsetCommandFunc(cmd *cobra.Command, args []string) error {
+	setting, err := lookupSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	path, err := scopedConfigFile()
+	if err != nil {
+		return err
+	}
+	removed, err := config.UnsetFileValue(path, setting.Key)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%s is not set in %s", setting.Key, path)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Removed %s from %s", setting.Key, path))
+	return nil
+}
+
+func configListCommandFunc(cmd *cobra.Command, args []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if configGlobalFlag || configLocalFlag {
+		path, err := scopedConfigFile()
+		if err != nil {
+			return err
+		}
+		for _, s := range config.Settings {
+			value, ok, err := config.GetFileValue(path, s.Key)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if configShowOriginFlag {
+				fmt.Fprintf(w, "file:%s\t", path)
+			}
+			fmt.Fprintf(w, "%s=%s\n", s.Key, displayValue(s, value))
+		}
+		return w.Flush()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Profile != "" {
+		fmt.Fprintf(w, "# profile: %s\n", cfg.Profile)
+	}
+	for _, s := range config.Settings {
+		if configShowOriginFlag {
+			fmt.Fprintf(w, "%s\t", cfg.Origin(s.Key))
+		}
+		fmt.Fprintf(w, "%s=%s\n", s.Key, displayValue(s, s.Get(cfg)))
+	}
+	return w.Flush()
+}
+
+func configEditCommandFunc(cmd *cobra.Command, args []string) error {
+	path, err := scopedConfigFile()
+	if err != nil {
+		return err
+	}
+
+	// Load would reject the invalid file the user wants to fix
+	return terminal.EditFile(path, config.LoadEditor())
+}
+
+func configPathCommandFunc(cmd *cobra.Command, args []string) error {
+	if configGlobalFlag || configLocalFlag {
+		path, err := scopedConfigFile()
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	}
+
+	globalPath, err := config.HomeFile()
+	if err != nil {
+		return err
+	}
+	localPath, err := localConfigFile()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("global\t%s\n", globalPath)
+	fmt.Printf("local\t%s\n", localPath)
+	return nil
+}
+
+func lookupSetting(key string) (config.Setting, error) {
+	setting, ok := config.LookupSetting(key)
+	if !ok {
+		return setting, fmt.Errorf("unknown config key %q", key)
+	}
+	return setting, nil
+}
+
+// scopedConfigFile returns the file selected by --global or --local, defaulting to local
+func scopedConfigFile() (string, error) {
+	if configGlobalFlag {
+		return config.HomeFile()
+	}
+	return localConfigFile()
+}
+
+// localConfigFile returns the repository config file: the nearest existing
+// .zeusrc, or a new one at the repository root or in the current directory.
+func localConfigFile() (string, error) {
+	if _, repoFile := config.Files(); repoFile != "" {
+		return repoFile, nil
+	}
+
+	if root, err := git.RepoRoot(); err == nil {
+		return filepath.Join(root, ".zeusrc"), nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", errors.New("failed to determine current directory")
+	}
+	return filepath.Join(dir, ".zeusrc"), nil
+}
+
+// displayValue masks secrets so that listing the config does not leak them
+func displayValue(s config.Setting, value string) string {
+	if s.Key != "api_key" || value == "" {
+		return value
+	}
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
@@ -0,0 +1,219 @@
+package command
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+type checkResult struct {
+	Name   string
+	Status checkStatus
+	Detail string
+}
+
+func NewDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that zeus-ai is set up correctly",
+		Long: `Check the git installation, the repository state, the configuration,
+and that the configured provider is reachable, knows the model and accepts
+the API key. Exits with an error when any check fails.`,
+		Args: cobra.NoArgs,
+		RunE: doctorCommandFunc,
+	}
+}
+
+func doctorCommandFunc(cmd *cobra.Command, args []string) error {
+	var results []checkResult
+	add := func(name string, status checkStatus, format string, a ...any) {
+		results = append(results, checkResult{Name: name, Status: status, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	checkGit(add)
+	checkRepository(add)
+
+	cfg, err := config.Load()
+	if err != nil {
+		add("config", checkFail, "%v", err)
+		add("provider", checkSkip, "configuration could not be loaded")
+	} else {
+		home, repo := config.Files()
+		files := []string{}
+		for _, f := range []string{repo, home} {
+			if f != "" {
+				files = append(files, f)
+			}
+		}
+		detail := "no config file found, using defaults"
+		if len(files) > 0 {
+			detail = "loaded " + strings.Join(files, ", ")
+		}
+		if cfg.Profile != "" {
+			detail += fmt.Sprintf(" (profile %s)", cfg.Profile)
+		}
+		add("config", checkPass, "%s", detail)
+
+		checkProvider(cfg, add)
+	}
+
+	printDoctorReport(results)
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+type addCheckFunc func(name string, status checkStatus, format string, a ...any)
+
+func checkGit(add addCheckFunc) {
+	if _, err := exec.LookPath("git"); err != nil {
+		add("git", checkFail, "git binary not found in PATH")
+		return
+	}
+
+	version, err := git.Version()
+	if err != nil {
+		add("git", checkFail, "%v", err)
+		return
+	}
+
+	// Older releases lack flags used by zeus-ai, such as git interpret-trailers options
+	parts := strings.SplitN(version, ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	if major < 2 || (major == 2 && minor < 20) {
+		add("git", checkWarn, "git %s is old, 2.20 or newer is recommended", version)
+		return
+	}
+
+	add("git", checkPass, "git %s", version)
+}
+
+func checkRepository(add addCheckFunc) {
+	if !git.IsGitRepository() {
+		add("repository", checkWarn, "not inside a git repository")
+		return
+	}
+
+	root, err := git.RepoRoot()
+	if err != nil {
+		add("repository", checkFail, "%v", err)
+		return
+	}
+
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		add("repository", checkFail, "%v", err)
+		return
+	}
+	if branch == "" {
+		add("repository", checkWarn, "%s has a detached HEAD", root)
+		return
+	}
+
+	add("repository", checkPass, "%s on branch %s", root, branch)
+}
+
+func checkProvider(cfg *config.Config, add addCheckFunc) {
+	inspector, err := newInspector(cfg)
+	if err != nil {
+		add("provider", checkFail, "%v", err)
+		return
+	}
+	add("provider", checkPass, "%s", cfg.Provider)
+
+	// Only send a real prompt once everything it depends on looks right
+	ready := false
+
+	models, err := inspector.ListModels()
+	if err != nil {
+		add("reachable", checkFail, "%v", err)
+		add("model", checkSkip, "provider not reachable")
+	} else {
+		add("reachable", checkPass, "%s", providerURL(cfg))
+		if _, ok := findModel(models, cfg.Model); ok {
+			add("model", checkPass, "%s is available", cfg.Model)
+			ready = true
+		} else {
+			add("model", checkFail, "%s is not available (%d models found)", cfg.Model, len(models))
+		}
+	}
+
+	if strings.EqualFold(cfg.Provider, "ollama") {
+		add("api key", checkSkip, "not required for ollama")
+	} else {
+		apiKey, err := resolveAPIKey(cfg)
+		if err == nil {
+			err = llm.CheckOpenRouterKey(providerURL(cfg), apiKey, llm.WithPolicy(providerPolicy(cfg)))
+		}
+		if err != nil {
+			add("api key", checkFail, "%v", err)
+			ready = false
+		} else {
+			add("api key", checkPass, "accepted")
+		}
+	}
+
+	if !ready {
+		add("response", checkSkip, "fix the checks above first")
+		return
+	}
+	latency, err := inspector.Ping()
+	if err != nil {
+		add("response", checkFail, "%v", err)
+		return
+	}
+	add("response", checkPass, "%s answered in %s", cfg.Model, latency.Round(time.Millisecond))
+}
+
+func printDoctorReport(results []checkResult) {
+	terminal.DividerColor.Println("\n┌───────────────────────────────────────────────────────┐")
+	terminal.TitleColor.Println("  ZEUS-AI DOCTOR")
+	terminal.DividerColor.Println("├───────────────────────────────────────────────────────┤")
+
+	for _, r := range results {
+		detail := strings.ReplaceAll(r.Detail, "\n", "\n"+strings.Repeat(" ", 16))
+		line := fmt.Sprintf("%-11s %s", r.Name, detail)
+		switch r.Status {
+		case checkPass:
+			terminal.SuccessColor.Println("  ✓", line)
+		case checkWarn:
+			terminal.WarningColor.Println("  !", line)
+		case checkFail:
+			terminal.ErrorColor.Println("  ✖", line)
+		case checkSkip:
+			terminal.BodyColor.Println("  -", line)
+		}
+	}
+
+	terminal.DividerColor.Println("└───────────────────────────────────────────────────────┘")
+}
@@ -0,0 +1,250 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	explainFormatFlag   string
+	explainOutputFlag   string
+	explainNoStreamFlag bool
+	explainMaxCountFlag int
+)
+
+func NewExplainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [<rev>|<from>..<to>|<file>]",
+		Short: "Explain a commit, a range of commits or the history of a file",
+		Long: `Explain in plain language what changed and why, using the commit messages as
+hints. The argument is a commit (HEAD by default), a range such as
+main..HEAD, or a file, whose last --max-count commits and uncommitted changes
+are explained.
+
+The explanation is streamed as the model writes it, unless --no-stream is
+given or it is written to a file with --output. With --format markdown it is
+split into summary, changes, why and risks sections.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: explainCommandFunc,
+	}
+
+	cmd.Flags().StringVar(&explainFormatFlag, "format", llm.ExplainText, "Output format ("+strings.Join(llm.ExplainFormats, ", ")+")")
+	cmd.Flags().StringVarP(&explainOutputFlag, "output", "o", "", "Write the explanation to a file instead of stdout")
+	cmd.Flags().BoolVar(&explainNoStreamFlag, "no-stream", false, "Print the explanation once it is complete")
+	cmd.Flags().IntVarP(&explainMaxCountFlag, "max-count", "n", 5, "Number of commits to explain for a file")
+
+	return cmd
+}
+
+// explainTarget is the change zeusctl explain describes
+type explainTarget struct {
+	// subject describes the change in the prompt, such as "commit abc1234"
+	subject       string
+	commits       []git.LogEntry
+	diff          string
+	before, after fileReader
+}
+
+func explainCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+	switch explainFormatFlag {
+	case llm.ExplainText, llm.ExplainMarkdown:
+	default:
+		return fmt.Errorf("unknown format %q: must be one of %s", explainFormatFlag, strings.Join(llm.ExplainFormats, ", "))
+	}
+	if explainMaxCountFlag < 1 {
+		return fmt.Errorf("--max-count must be at least 1")
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	arg := "HEAD"
+	if len(args) == 1 {
+		arg = args[0]
+	}
+	target, err := resolveExplainTarget(arg)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(target.diff) == "" {
+		return fmt.Errorf("no changes to explain in %s", target.subject)
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	var commits []string
+	for _, c := range target.commits {
+		commits = append(commits, fmt.Sprintf("%.7s by %s on %s: %s", c.Commit, c.Author, c.Date, c.Message()))
+	}
+	hints := apiHints(apiChanges(target.diff, target.before, target.after), "")
+
+	if explainOutputFlag != "" || explainNoStreamFlag {
+		var out strings.Builder
+		// The spinner would end up in the explanation when stdout is redirected
+		stopSpinner := func() {}
+		if explainOutputFlag != "" || terminal.IsOutputTerminal() {
+			stopSpinner = terminal.ShowSpinner(fmt.Sprintf("Explaining %s...", target.subject))
+		}
+		res, err := llm.ExplainChanges(provider, &out, target.subject, commits, target.diff, explainFormatFlag, hints)
+		stopSpinner()
+		if err != nil {
+			return fmt.Errorf("failed to explain changes: %w", err)
+		}
+		text := strings.TrimSpace(out.String()) + "\n"
+
+		if explainOutputFlag == "" {
+			fmt.Fprint(os.Stdout, text)
+			// Usage goes to stderr so that stdout holds only the explanation
+			terminal.BodyColor.Fprintf(os.Stderr, "  %s\n", usageSummary(res.Stats))
+			return nil
+		}
+
+		showUsage(res.Stats)
+		if err = os.WriteFile(explainOutputFlag, []byte(text), 0o644); err != nil {
+			return fmt.Errorf("failed to write explanation: %w", err)
+		}
+		terminal.ShowSuccess(fmt.Sprintf("Wrote the explanation to %s", explainOutputFlag))
+		return nil
+	}
+
+	w := &streamWriter{w: os.Stdout, stop: func() {}}
+	if terminal.IsOutputTerminal() {
+		w.stop = terminal.ShowSpinner(fmt.Sprintf("Explaining %s...", target.subject))
+	}
+	res, err := llm.ExplainChanges(provider, w, target.subject, commits, target.diff, explainFormatFlag, hints)
+	w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to explain changes: %w", err)
+	}
+	terminal.BodyColor.Fprintf(os.Stderr, "  %s\n", usageSummary(res.Stats))
+	return nil
+}
+
+// resolveExplainTarget reads the diff and commits of a range, a revision or
+// a file, in that order of preference
+func resolveExplainTarget(arg string) (explainTarget, error) {
+	if strings.Contains(arg, "..") {
+		from, to := parseRange(arg)
+		entries, err := git.Log(from, to)
+		if err != nil {
+			return explainTarget{}, err
+		}
+		if len(entries) == 0 {
+			return explainTarget{}, fmt.Errorf("no commits in %s..%s", from, to)
+		}
+		diff, err := git.GetRangeDiff(from, to)
+		if err != nil {
+			return explainTarget{}, err
+		}
+		return explainTarget{
+			subject: fmt.Sprintf("the %d commits in %s..%s", len(entries), from, to),
+			commits: entries,
+			diff:    diff,
+			before:  atRevision(from),
+			after:   atRevision(to),
+		}, nil
+	}
+
+	if _, err := git.ResolveCommit(arg); err == nil {
+		entry, err := git.ShowCommit(arg)
+		if err != nil {
+			return explainTarget{}, err
+		}
+		base, err := git.BaseOf(entry.Commit)
+		if err != nil {
+			return explainTarget{}, err
+		}
+		diff, err := git.GetCommitDiff(entry.Commit)
+		if err != nil {
+			return explainTarget{}, err
+		}
+		return explainTarget{
+			subject: fmt.Sprintf("commit %.7s", entry.Commit),
+			commits: []git.LogEntry{entry},
+			diff:    diff,
+			before:  atRevision(base),
+			after:   atRevision(entry.Commit),
+		}, nil
+	}
+
+	if _, err := os.Stat(arg); err != nil {
+		return explainTarget{}, fmt.Errorf("%q is neither a revision nor a file", arg)
+	}
+	entries, err := git.FileLog(arg, explainMaxCountFlag)
+	if err != nil {
+		return explainTarget{}, err
+	}
+	if len(entries) == 0 {
+		return explainTarget{}, fmt.Errorf("%s has no commits", arg)
+	}
+	base, err := git.BaseOf(entries[0].Commit)
+	if err != nil {
+		return explainTarget{}, err
+	}
+	diff, err := git.GetPathDiff(base, "", arg)
+	if err != nil {
+		return explainTarget{}, err
+	}
+	return explainTarget{
+		subject: fmt.Sprintf("the last %d commits to %s and its uncommitted changes", len(entries), arg),
+		commits: entries,
+		diff:    diff,
+		before:  atRevision(base),
+		after:   inWorkTree(),
+	}, nil
+}
+
+// streamWriter stops the spinner before the first piece of the explanation
+// is written and remembers whether the output ends with a newline
+type streamWriter struct {
+	w       io.Writer
+	stop    func()
+	started bool
+	last    byte
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if !s.started {
+		s.started = true
+		s.stop()
+	}
+	s.last = p[len(p)-1]
+	return s.w.Write(p)
+}
+
+// Close stops the spinner if nothing was written and ends the output with a
+// newline
+func (s *streamWriter) Close() error {
+	if !s.started {
+		s.stop()
+		return nil
+	}
+	if s.last != '\n' {
+		_, err := io.WriteString(s.w, "\n")
+		return err
+	}
+	return nil
+}
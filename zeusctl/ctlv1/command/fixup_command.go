@@ -0,0 +1,159 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+// fixupConfirmCount is how many of the best candidates are shown to the model
+const fixupConfirmCount = 3
+
+var (
+	fixupDepthFlag int
+	fixupLLMFlag   bool
+)
+
+func NewFixupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fixup",
+		Short: "Commit staged changes as a fixup for the earlier commit they belong to",
+		Long: `Find the unpushed commit that the staged changes most likely belong to and
+create a "fixup!" commit for it, so that a later git rebase --autosquash folds
+the changes in.
+
+Candidates are ranked by the commits git blame attributes the changed lines
+to, by the lines each commit touched and by the files they share. With --llm
+the best candidates are also shown to the model, whose pick is preselected.`,
+		Args: cobra.NoArgs,
+		RunE: fixupCommandFunc,
+	}
+
+	// Flags override the matching config keys only when set, as for suggest
+	addSigningFlags(cmd)
+	cmd.Flags().Bool("dry-run", false, "Show the matching commits but don't create the fixup commit")
+	cmd.Flags().IntVar(&fixupDepthFlag, "depth", 10, "Number of recent unpushed commits to consider")
+	cmd.Flags().BoolVar(&fixupLLMFlag, "llm", false, "Ask the model to confirm the best match")
+
+	return cmd
+}
+
+func fixupCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+	if fixupDepthFlag < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+
+	patch, err := git.GetStagedPatch()
+	if err != nil {
+		return err
+	}
+	hunks := git.ParsePatch(patch)
+	if len(hunks) == 0 {
+		return fmt.Errorf("no staged changes to commit")
+	}
+
+	candidates, err := git.RankFixupCandidates(hunks, fixupDepthFlag)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("none of the last %d unpushed commits touch the staged changes; use `zeusctl suggest` instead", fixupDepthFlag)
+	}
+
+	choice := 0
+	if fixupLLMFlag {
+		choice, err = confirmFixup(cfg, candidates)
+		if err != nil {
+			return err
+		}
+	}
+
+	options := make([]string, 0, len(candidates)+1)
+	for _, c := range candidates {
+		options = append(options, fmt.Sprintf("%.7s %s (%d blamed lines, %d overlapping hunks, %d shared files)",
+			c.Commit, c.Subject, c.BlamedLines, c.TouchedHunks, c.SharedFiles))
+	}
+
+	if cfg.DryRun {
+		terminal.TitleColor.Println("Matching commits:")
+		for i, option := range options {
+			terminal.OptionColor.Printf("  %d. %s\n", i+1, option)
+		}
+		terminal.ShowSuccess(fmt.Sprintf("Dry run - would create fixup! %s", candidates[choice].Subject))
+		return nil
+	}
+
+	options = append(options, "Quit without committing")
+	selected, err := terminal.Select("Create a fixup commit for:", options, choice)
+	if err != nil {
+		return err
+	}
+	if selected == len(candidates) {
+		return errors.New("fixup cancelled, nothing was committed")
+	}
+
+	target := candidates[selected]
+	if err = git.Fixup(target.Commit, commitOptions(cfg)); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Created fixup! %s; fold it in with `git rebase -i --autosquash %.7s~`", target.Subject, target.Commit))
+	return nil
+}
+
+// confirmFixup asks the model which of the best candidates the staged changes
+// belong to and returns its index, falling back to the top ranked candidate
+// when the model picks none of them
+func confirmFixup(cfg *config.Config, candidates []git.FixupCandidate) (int, error) {
+	diff, err := git.GetDiff(true)
+	if err != nil {
+		return 0, err
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	shown := candidates[:min(len(candidates), fixupConfirmCount)]
+	commits := make([]llm.FixupCommit, len(shown))
+	for i, c := range shown {
+		commitDiff, diffErr := git.GetCommitDiff(c.Commit)
+		if diffErr != nil {
+			return 0, diffErr
+		}
+		commits[i] = llm.FixupCommit{Subject: c.Subject, Diff: commitDiff}
+	}
+
+	stopSpinner := terminal.ShowSpinner("Asking the model to confirm the match...")
+	choice, reason, res, err := llm.ConfirmFixup(provider, diff, commits)
+	stopSpinner()
+	if err != nil {
+		return 0, fmt.Errorf("failed to confirm fixup target: %w", err)
+	}
+	showUsage(res.Stats)
+
+	if choice < 0 {
+		terminal.ShowWarning(fmt.Sprintf("The model found no matching commit: %s", reason))
+		return 0, nil
+	}
+	terminal.ShowSuccess(fmt.Sprintf("The model picked %.7s: %s", shown[choice].Commit, reason))
+	return choice, nil
+}
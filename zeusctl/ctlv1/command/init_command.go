@@ -1,50 +1,406 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/credential"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
 	"github.com/amosehiguese/zeus-ai/internal/terminal"
 )
 
 var (
-	providerFlag string
-	apiKeyFlag   string
-	modelFlag    string
-	styleFlag    string
+	initNonInteractiveFlag bool
+	initGlobalFlag         bool
 )
 
 func NewInitCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize zeus-ai configuration",
-		Long:  `Initialize zeus-ai configuration by creating a .zeusrc file in the current directory`,
-		RunE:  initCommandFunc,
+		Long: `Initialize zeus-ai configuration with an interactive wizard.
+
+The wizard detects a local Ollama server, asks for the provider and model,
+tests the connection and writes the settings to the repository or global
+.zeusrc, keeping any other settings already in the file.
+
+With --non-interactive, or when stdin is not a terminal, the flag values are
+written to .zeusrc in the current directory (or ~/.zeusrc with --global).`,
+		RunE: initCommandFunc,
 	}
 
-	cmd.Flags().StringVar(&providerFlag, "provider", "ollama", "LLM provider (ollama, openrouter)")
-	cmd.Flags().StringVar(&apiKeyFlag, "api-key", "", "API key for the provider")
-	cmd.Flags().StringVar(&modelFlag, "model", "deepseek-coder", "Model to use")
-	cmd.Flags().StringVar(&styleFlag, "style", "conventional", "Default commit style")
+	// Flags override the matching config keys (provider, api_key, model,
+	// default_style); unset flags fall back to the current effective config
+	cmd.Flags().String("provider", "ollama", "LLM provider (ollama, openrouter)")
+	cmd.Flags().String("api-key", "", "API key for the provider")
+	cmd.Flags().String("model", "mistral", "Model to use")
+	cmd.Flags().String("style", "conventional", "Default commit style")
+	cmd.Flags().BoolVar(&initNonInteractiveFlag, "non-interactive", false, "Write the flag values without prompting")
+	cmd.Flags().BoolVar(&initGlobalFlag, "global", false, "Write ~/.zeusrc instead of the repository .zeusrc")
 
 	return cmd
 }
 
 func initCommandFunc(cmd *cobra.Command, args []string) error {
-	config := fmt.Sprintf(`# zeus-ai configuration
-provider: %s
-api_key: %s
-model: %s
-default_style: %s
-`, providerFlag, apiKeyFlag, modelFlag, styleFlag)
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if initNonInteractiveFlag || !terminal.IsInteractive() {
+		return initNonInteractive(cmd, cfg)
+	}
+	return initWizard(cfg)
+}
+
+func initNonInteractive(cmd *cobra.Command, cfg *config.Config) error {
+	path := ".zeusrc"
+	if initGlobalFlag {
+		home, err := config.HomeFile()
+		if err != nil {
+			return err
+		}
+		path = home
+	}
+
+	values := map[string]string{
+		"provider":      cfg.Provider,
+		"model":         cfg.Model,
+		"default_style": cfg.DefaultStyle,
+	}
+
+	// Only persist a key passed explicitly, never one resolved from the
+	// environment or another config file
+	if cmd.Flags().Changed("api-key") && cfg.APIKey != "" {
+		if !initGlobalFlag {
+			if err := checkSecretFile(path); err != nil {
+				return err
+			}
+		}
+		values["api_key"] = cfg.APIKey
+	}
+
+	if err := writeConfigValues(path, values); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Configuration written to %s", path))
+	return nil
+}
 
-	err := os.WriteFile(".zeusrc", []byte(config), 0o600)
+func initWizard(cfg *config.Config) error {
+	printInitHeader()
+
+	// Detect a local Ollama server
+	ollamaModels, ollamaErr := llm.NewOllamaProvider("", llm.WithBaseURL(ollamaURL(cfg)), llm.WithPolicy(providerPolicy(cfg))).ListModels()
+	if ollamaErr == nil {
+		terminal.ShowSuccess(fmt.Sprintf("Found a running Ollama server with %d installed models", len(ollamaModels)))
+	} else {
+		terminal.ShowWarning("No local Ollama server detected")
+	}
+
+	// Provider
+	providers := []string{"ollama", "openrouter"}
+	def := 0
+	if ollamaErr != nil || strings.EqualFold(cfg.Provider, "openrouter") {
+		def = 1
+	}
+	idx, err := terminal.Select("\nWhich provider do you want to use?", []string{
+		"ollama (local models)",
+		"openrouter (hosted models, requires an API key)",
+	}, def)
+	if err != nil {
+		return err
+	}
+	if providers[idx] != cfg.Provider {
+		cfg.Model = ""
+	}
+	cfg.Provider = providers[idx]
+
+	// Model
+	if cfg.Model, err = askModel(cfg, ollamaModels); err != nil {
+		return err
+	}
+
+	// Commit style
+	styles := []string{"conventional", "simple"}
+	def = 0
+	if cfg.DefaultStyle == "simple" {
+		def = 1
+	}
+	if idx, err = terminal.Select("\nDefault commit style?", styles, def); err != nil {
+		return err
+	}
+	cfg.DefaultStyle = styles[idx]
+
+	// Scope
+	path, global, err := askScope()
+	if err != nil {
+		return err
+	}
+
+	// API key
+	writeKey := false
+	if cfg.Provider != "ollama" {
+		if writeKey, err = askAPIKey(cfg, path, global); err != nil {
+			return err
+		}
+	}
+
+	// Connectivity
+	if err = testConnectivity(cfg); err != nil {
+		terminal.ShowError(err.Error())
+		ok, confirmErr := terminal.Confirm("Save the configuration anyway?")
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if !ok {
+			return errors.New("configuration not saved")
+		}
+	}
+
+	values := map[string]string{
+		"provider":      cfg.Provider,
+		"model":         cfg.Model,
+		"default_style": cfg.DefaultStyle,
+	}
+	if writeKey {
+		values["api_key"] = cfg.APIKey
+	}
+
+	if err = writeConfigValues(path, values); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Configuration written to %s", path))
+	return nil
+}
+
+func askModel(cfg *config.Config, ollamaModels []llm.ModelInfo) (string, error) {
+	if cfg.Provider == "ollama" && len(ollamaModels) > 0 {
+		options := make([]string, 0, len(ollamaModels)+1)
+		def := 0
+		for i, m := range ollamaModels {
+			options = append(options, m.ID)
+			if m.ID == cfg.Model || strings.TrimSuffix(m.ID, ":latest") == cfg.Model {
+				def = i
+			}
+		}
+		options = append(options, "other...")
+
+		idx, err := terminal.Select("\nWhich model do you want to use?", options, def)
+		if err != nil {
+			return "", err
+		}
+		if idx < len(ollamaModels) {
+			return ollamaModels[idx].ID, nil
+		}
+	}
+
+	def := cfg.Model
+	if def == "" {
+		def = defaultModel(cfg.Provider)
+	}
+	fmt.Println()
+	return terminal.Prompt("Model name", def)
+}
+
+func askScope() (path string, global bool, err error) {
+	home, err := config.HomeFile()
+	if err != nil {
+		return "", false, err
+	}
+	local, err := localConfigFile()
+	if err != nil {
+		return "", false, err
+	}
+
+	def := 0
+	if !git.IsGitRepository() {
+		def = 1
+	}
+	idx, err := terminal.Select("\nWhere should the configuration be saved?", []string{
+		fmt.Sprintf("This repository (%s)", local),
+		fmt.Sprintf("Global (%s)", home),
+	}, def)
 	if err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+		return "", false, err
+	}
+
+	if idx == 1 {
+		return home, true, nil
+	}
+	return local, false, nil
+}
+
+// askAPIKey makes sure a key is available for hosted providers and reports
+// whether it should be written to the config file.
+func askAPIKey(cfg *config.Config, path string, global bool) (bool, error) {
+	store, storeErr := credential.DefaultStore()
+	if key, source, err := credential.Resolve(cfg, store); err == nil && key != "" {
+		terminal.ShowSuccess(fmt.Sprintf("Using the existing API key from %s", source))
+		cfg.APIKey = key
+		return false, nil
+	}
+
+	options := []string{
+		"Store it in the OS keyring",
+		fmt.Sprintf("Write it to %s", path),
+		"Skip (set ZEUS_API_KEY, api_key_cmd or api_key_file later)",
+	}
+	def := 0
+	if storeErr != nil {
+		options[0] += " (unavailable)"
+		def = 2
+	}
+
+	idx, err := terminal.Select("\nHow should the API key be stored?", options, def)
+	if err != nil {
+		return false, err
+	}
+	if idx == 2 {
+		return false, nil
+	}
+	if idx == 0 && storeErr != nil {
+		return false, storeErr
+	}
+	if idx == 1 && !global {
+		if err = checkSecretFile(path); err != nil {
+			return false, err
+		}
+	}
+
+	key, err := terminal.ReadSecret(fmt.Sprintf("API key for %s", cfg.Provider))
+	if err != nil {
+		return false, err
+	}
+	if key == "" {
+		return false, errors.New("no API key given")
+	}
+	cfg.APIKey = key
+
+	if idx == 0 {
+		if err = store.Set(cfg.Provider, key); err != nil {
+			return false, err
+		}
+		terminal.ShowSuccess(fmt.Sprintf("Stored the API key in %s", store.Name()))
+		return false, nil
+	}
+	return true, nil
+}
+
+// testConnectivity pings the provider with a tiny prompt
+func testConnectivity(cfg *config.Config) error {
+	inspector, err := newInspector(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	stopSpinner := terminal.ShowSpinner(fmt.Sprintf("Testing %s with %s...", cfg.Provider, cfg.Model))
+	latency, err := inspector.Ping()
+	stopSpinner()
+	if err != nil {
+		return fmt.Errorf("connectivity test failed: %w", err)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("%s answered using %s in %s", cfg.Provider, cfg.Model, latency.Round(time.Millisecond)))
+	return nil
+}
+
+// writeConfigValues merges values into the config file, keeping every other key
+func writeConfigValues(path string, values map[string]string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		if err = os.WriteFile(path, []byte("# zeus-ai configuration\n"), 0o600); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+	} else {
+		terminal.ShowWarning(fmt.Sprintf("Updating existing %s", path))
+	}
+
+	for _, s := range config.Settings {
+		value, ok := values[s.Key]
+		if !ok {
+			continue
+		}
+		if err := config.SetFileValue(path, s.Key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ollamaURL(cfg *config.Config) string {
+	if cfg.BaseURL != "" && strings.EqualFold(cfg.Provider, "ollama") {
+		return cfg.BaseURL
+	}
+	return llm.DefaultOllamaURL
+}
+
+// providerURL returns the endpoint the configured provider talks to
+func providerURL(cfg *config.Config) string {
+	switch {
+	case cfg.BaseURL != "":
+		return cfg.BaseURL
+	case strings.EqualFold(cfg.Provider, "ollama"):
+		return llm.DefaultOllamaURL
+	default:
+		return llm.DefaultOpenRouterURL
+	}
+}
+
+func defaultModel(provider string) string {
+	if provider == "openrouter" {
+		return "mistralai/mistral-small-3.1-24b-instruct:free"
+	}
+	return "mistral"
+}
+
+func printInitHeader() {
+	terminal.DividerColor.Println("\n┌───────────────────────────────────────────────────────┐")
+	terminal.TitleColor.Println("  ZEUS-AI SETUP")
+	terminal.DividerColor.Println("└───────────────────────────────────────────────────────┘")
+}
+
+// checkSecretFile refuses to let a secret be written to a file that git would
+// commit: one that is already tracked or is not covered by .gitignore.
+func checkSecretFile(path string) error {
+	if !git.IsGitRepository() {
+		return nil
+	}
+
+	tracked, err := git.IsTracked(path)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return fmt.Errorf("refusing to write api_key to %s: the file is tracked by git. "+
+			"Store the key with `zeusctl auth login` or use api_key_cmd/api_key_file instead", path)
+	}
+
+	ignored, err := git.IsIgnored(path)
+	if err != nil {
+		return err
+	}
+	if !ignored {
+		return fmt.Errorf("refusing to write api_key to %s: the file is not ignored by git. "+
+			"Add it to .gitignore, or store the key with `zeusctl auth login` or api_key_cmd/api_key_file instead", path)
 	}
 
-	terminal.ShowSuccess("✅ Configuration file .zeusrc created successfully!")
 	return nil
 }
@@ -0,0 +1,142 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+func NewModelsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Inspect the models offered by the provider",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [filter]",
+		Short: "List the models available from the configured provider",
+		Long: `List the models available from the configured provider with their context
+length and pricing where the provider publishes them. An optional filter
+only shows models whose name contains it. The configured model is marked
+with *.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: modelsListCommandFunc,
+	}
+	listCmd.Flags().String("provider", "", "Provider to query instead of the configured one")
+
+	cmd.AddCommand(listCmd)
+	return cmd
+}
+
+func modelsListCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	inspector, err := newInspector(cfg)
+	if err != nil {
+		return err
+	}
+
+	models, err := inspector.ListModels()
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	if len(args) == 1 {
+		filter := strings.ToLower(args[0])
+		matching := models[:0]
+		for _, m := range models {
+			if strings.Contains(strings.ToLower(m.ID), filter) {
+				matching = append(matching, m)
+			}
+		}
+		models = matching
+	}
+
+	if len(models) == 0 {
+		terminal.ShowWarning("No models found")
+		return nil
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tMODEL\tCONTEXT\tPROMPT $/1M\tCOMPLETION $/1M\tSIZE")
+	for _, m := range models {
+		current := ""
+		if m.ID == cfg.Model || strings.TrimSuffix(m.ID, ":latest") == cfg.Model {
+			current = "*"
+		}
+
+		prompt, completion := "-", "-"
+		if m.Pricing != nil {
+			prompt = formatPrice(m.Pricing.Prompt)
+			completion = formatPrice(m.Pricing.Completion)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			current,
+			m.ID,
+			formatContext(m.ContextLength),
+			prompt,
+			completion,
+			formatSize(m.Size),
+		)
+	}
+
+	return w.Flush()
+}
+
+// formatPrice converts a per-token price to dollars per million tokens
+func formatPrice(perToken float64) string {
+	if perToken == 0 {
+		return "free"
+	}
+	return fmt.Sprintf("%.2f", perToken*1_000_000)
+}
+
+func formatContext(tokens int) string {
+	switch {
+	case tokens == 0:
+		return "-"
+	case tokens >= 1000:
+		return fmt.Sprintf("%dk", tokens/1000)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
+
+func formatSize(bytes int64) string {
+	const gb = 1 << 30
+	switch {
+	case bytes == 0:
+		return "-"
+	case bytes >= gb:
+		return fmt.Sprintf("%.1f GB", float64(bytes)/gb)
+	default:
+		return fmt.Sprintf("%d MB", bytes>>20)
+	}
+}
+
+// findModel returns the model matching id, treating Ollama's ":latest" tag as optional
+func findModel(models []llm.ModelInfo, id string) (llm.ModelInfo, bool) {
+	for _, m := range models {
+		if m.ID == id || strings.TrimSuffix(m.ID, ":latest") == id {
+			return m, true
+		}
+	}
+	return llm.ModelInfo{}, false
+}
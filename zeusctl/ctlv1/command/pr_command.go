@@ -0,0 +1,188 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	prBaseFlag       string
+	prTemplateFlag   string
+	prNoTemplateFlag bool
+	prOutputFlag     string
+)
+
+// prTemplates are the locations GitHub looks for a pull request template in,
+// relative to the repository root
+var prTemplates = []string{
+	".github/pull_request_template.md",
+	"pull_request_template.md",
+	"docs/pull_request_template.md",
+}
+
+func NewPRCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Work with pull requests for the current branch",
+	}
+
+	describeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Generate a pull request title and description",
+		Long: `Generate a pull request title and markdown description from the commits and
+the combined diff between the merge-base with the base branch and HEAD.
+
+The description follows the repository's pull request template when there is
+one, such as .github/PULL_REQUEST_TEMPLATE.md, and otherwise has summary,
+changes, testing and risks sections. The title and description are printed,
+separated by a blank line, or written to the file given with --output.`,
+		Args: cobra.NoArgs,
+		RunE: prDescribeCommandFunc,
+	}
+	describeCmd.Flags().StringVar(&prBaseFlag, "base", "", "Branch the pull request targets (default: the remote's default branch, main or master)")
+	describeCmd.Flags().StringVar(&prTemplateFlag, "template", "", "Template to use as the description skeleton")
+	describeCmd.Flags().BoolVar(&prNoTemplateFlag, "no-template", false, "Ignore the repository's pull request template")
+	describeCmd.Flags().StringVarP(&prOutputFlag, "output", "o", "", "Write the description to a file instead of stdout")
+
+	cmd.AddCommand(describeCmd)
+	return cmd
+}
+
+func prDescribeCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	base := prBaseFlag
+	if base == "" {
+		if base, err = git.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+
+	mergeBase, err := git.MergeBase(base, "HEAD")
+	if err != nil {
+		return err
+	}
+	entries, err := git.Log(mergeBase, "HEAD")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("HEAD has no commits that are not on %s", base)
+	}
+	diff, err := git.GetRangeDiff(mergeBase, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	template, err := prTemplate()
+	if err != nil {
+		return err
+	}
+
+	ticketID, err := branchTicket(cfg)
+	if err != nil {
+		return err
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	commits := make([]string, len(entries))
+	for i, e := range entries {
+		commits[i] = e.Message()
+	}
+
+	// The spinner would end up in the description when stdout is redirected
+	stopSpinner := func() {}
+	if prOutputFlag != "" || terminal.IsOutputTerminal() {
+		stopSpinner = terminal.ShowSpinner(fmt.Sprintf("Describing %d commits against %s...", len(entries), base))
+	}
+	pr, res, err := llm.DescribePullRequest(provider, commits, diff, template, ticketHints(ticketID))
+	stopSpinner()
+	if err != nil {
+		return fmt.Errorf("failed to describe pull request: %w", err)
+	}
+
+	if prOutputFlag == "" {
+		// Usage goes to stderr so that stdout holds only the description
+		fmt.Fprintln(os.Stdout, pr.String())
+		terminal.BodyColor.Fprintf(os.Stderr, "  %s\n", usageSummary(res.Stats))
+		return nil
+	}
+
+	showUsage(res.Stats)
+	if err = os.WriteFile(prOutputFlag, []byte(pr.String()+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write description: %w", err)
+	}
+	terminal.ShowSuccess(fmt.Sprintf("Wrote the pull request description to %s", prOutputFlag))
+	return nil
+}
+
+// prTemplate reads the template given with --template or the repository's
+// pull request template, returning an empty string when there is none
+func prTemplate() (string, error) {
+	if prNoTemplateFlag {
+		return "", nil
+	}
+	if prTemplateFlag != "" {
+		data, err := os.ReadFile(prTemplateFlag)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		return string(data), nil
+	}
+
+	root, err := git.RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range prTemplates {
+		path, ok := findFileFold(root, name)
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// findFileFold finds name below root, ignoring the case of the file name as
+// GitHub does for templates
+func findFileFold(root, name string) (string, bool) {
+	dir, file := filepath.Split(filepath.FromSlash(name))
+	entries, err := os.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return "", false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(e.Name(), file) {
+			return filepath.Join(root, dir, e.Name()), true
+		}
+	}
+	return "", false
+}
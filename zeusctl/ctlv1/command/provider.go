@@ -0,0 +1,124 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/credential"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+// newProvider creates the configured LLM provider, enforcing the repository
+// policy and wiring in the audit log when enabled.
+func newProvider(cfg *config.Config) (llm.Provider, error) {
+	policy := providerPolicy(cfg)
+	// Reject a forbidden provider before api_key_cmd or the keyring is consulted
+	if err := policy.Check(cfg.Provider, cfg.BaseURL); err != nil {
+		return nil, err
+	}
+
+	opts := []llm.Option{llm.WithPolicy(policy)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, llm.WithBaseURL(cfg.BaseURL))
+	}
+	repo, _ := git.RepoRoot()
+	if cfg.Audit {
+		auditLog, err := openAuditLog()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, llm.WithAuditLog(auditLog, repo))
+	}
+
+	if cfg.UsageLedger {
+		ledger, err := openLedger()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, llm.WithLedger(ledger, repo))
+	}
+	opts = append(opts, llm.WithWarnings(func(err error) {
+		// Warnings go to stderr so that they never mix with piped output
+		terminal.WarningColor.Fprintf(os.Stderr, "! %s\n", err)
+	}))
+
+	apiKey, err := resolveAPIKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return llm.NewProvider(cfg.Provider, apiKey, cfg.Model, opts...)
+}
+
+// providerPolicy returns the repository policy that every connection to a
+// provider must pass
+func providerPolicy(cfg *config.Config) llm.Policy {
+	return llm.Policy{
+		AllowedProviders: cfg.Policy.AllowedProviders,
+		LocalOnly:        cfg.Policy.Network == config.NetworkLocalOnly,
+		Source:           cfg.Policy.Source,
+	}
+}
+
+// resolveAPIKey finds the API key for providers that need one, consulting
+// api_key, api_key_cmd, api_key_file and the OS keyring in that order.
+func resolveAPIKey(cfg *config.Config) (string, error) {
+	if strings.EqualFold(cfg.Provider, "ollama") {
+		return "", nil
+	}
+
+	// The keyring is optional; without it the other sources still work
+	store, _ := credential.DefaultStore()
+	key, _, err := credential.Resolve(cfg, store)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve API key: %w", err)
+	}
+	return key, nil
+}
+
+func openAuditLog() (*llm.AuditLog, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate state directory: %w", err)
+	}
+
+	return llm.NewAuditLog(filepath.Join(dir, "audit")), nil
+}
+
+func openLedger() (*llm.Ledger, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate state directory: %w", err)
+	}
+
+	return llm.NewLedger(filepath.Join(dir, "usage.jsonl")), nil
+}
+
+func openCache() (*llm.Cache, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+
+	return llm.NewCache(filepath.Join(dir, "suggestions")), nil
+}
+
+// newInspector creates the configured provider and returns its model listing
+// and health check capability.
+func newInspector(cfg *config.Config) (llm.Inspector, error) {
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	inspector, ok := provider.(llm.Inspector)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support listing models or ping", cfg.Provider)
+	}
+	return inspector, nil
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var providerPingCountFlag int
+
+func NewProviderCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "provider",
+		Short: "Check the configured LLM provider",
+	}
+
+	pingCmd := &cobra.Command{
+		Use:   "ping",
+		Short: "Measure the provider's latency with a tiny prompt",
+		Long: `Send a tiny prompt to the configured provider and model and report how long
+the answer took. Each ping is a real generation request and is recorded in
+the audit log when auditing is enabled.`,
+		Args: cobra.NoArgs,
+		RunE: providerPingCommandFunc,
+	}
+	pingCmd.Flags().String("provider", "", "Provider to ping instead of the configured one")
+	pingCmd.Flags().String("model", "", "Model to ping instead of the configured one")
+	pingCmd.Flags().IntVarP(&providerPingCountFlag, "count", "c", 1, "Number of pings to send")
+
+	cmd.AddCommand(pingCmd)
+	return cmd
+}
+
+func providerPingCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	inspector, err := newInspector(cfg)
+	if err != nil {
+		return err
+	}
+
+	var latencies []time.Duration
+	for i := 0; i < max(providerPingCountFlag, 1); i++ {
+		latency, err := inspector.Ping()
+		if err != nil {
+			return err
+		}
+		latencies = append(latencies, latency)
+		terminal.ShowSuccess(fmt.Sprintf("%s/%s answered in %s", cfg.Provider, cfg.Model, latency.Round(time.Millisecond)))
+	}
+
+	if len(latencies) > 1 {
+		lowest, highest, total := latencies[0], latencies[0], time.Duration(0)
+		for _, l := range latencies {
+			lowest, highest, total = min(lowest, l), max(highest, l), total+l
+		}
+		avg := total / time.Duration(len(latencies))
+		fmt.Printf("min/avg/max = %s/%s/%s\n",
+			lowest.Round(time.Millisecond), avg.Round(time.Millisecond), highest.Round(time.Millisecond))
+	}
+
+	return nil
+}
@@ -0,0 +1,339 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/api/version"
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/review"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	reviewStagedFlag bool
+	reviewRangeFlag  string
+	reviewFormatFlag string
+	reviewOutputFlag string
+	reviewForceFlag  bool
+)
+
+// reviewFormats are the output formats of zeusctl review
+var reviewFormats = []string{"text", "json", "sarif"}
+
+// hookMarker identifies pre-commit hooks installed by zeusctl review hook
+const hookMarker = "# Installed by zeusctl review hook"
+
+func NewReviewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review staged changes or a range of commits",
+		Long: `Ask the LLM to review the staged changes, or the changes in a range of commits
+with --range A..B, and report findings with their file, line, severity,
+category and message.
+
+Findings are shown next to the diff lines they refer to, or printed as JSON or
+SARIF with --format. With --fail-on (or review_fail_on), the command fails
+when any finding is at or above the given severity, which makes it usable as
+a pre-commit gate; see zeusctl review hook. A gate passes when there is
+nothing to review, and skips the review with a warning when the provider
+fails, unless --fail-on-error is given.`,
+		Args: cobra.NoArgs,
+		RunE: reviewCommandFunc,
+	}
+	cmd.Flags().BoolVar(&reviewStagedFlag, "staged", false, "Review the staged changes (default)")
+	cmd.Flags().StringVar(&reviewRangeFlag, "range", "", "Review the changes in a range of commits, such as main..HEAD")
+	cmd.Flags().StringVar(&reviewFormatFlag, "format", "text", "Output format ("+strings.Join(reviewFormats, ", ")+")")
+	cmd.Flags().StringVarP(&reviewOutputFlag, "output", "o", "", "Write the findings to a file instead of stdout")
+	cmd.Flags().String("fail-on", "none", "Fail when a finding is at or above this severity ("+strings.Join(llm.Severities, ", ")+" or none)")
+	cmd.Flags().Bool("fail-on-error", false, "With --fail-on, fail when the review cannot be run instead of skipping it")
+	cmd.MarkFlagsMutuallyExclusive("staged", "range")
+
+	hookCmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Install a pre-commit hook that blocks commits with serious findings",
+		Long: `Install a pre-commit hook that runs zeusctl review on the staged changes and
+blocks the commit when a finding is at or above the --fail-on severity
+(review_fail_on, or high when that is none). Provider failures let the commit
+through with a warning unless --fail-on-error is given. Bypass the hook for a
+single commit with git commit --no-verify.`,
+		Args: cobra.NoArgs,
+		RunE: reviewHookCommandFunc,
+	}
+	hookCmd.Flags().String("fail-on", "none", "Severity that blocks a commit ("+strings.Join(llm.Severities, ", ")+")")
+	hookCmd.Flags().Bool("fail-on-error", false, "Block the commit when the review cannot be run")
+	hookCmd.Flags().BoolVar(&reviewForceFlag, "force", false, "Replace an existing pre-commit hook")
+
+	cmd.AddCommand(hookCmd)
+	return cmd
+}
+
+func reviewCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+	switch reviewFormatFlag {
+	case "text", "json", "sarif":
+	default:
+		return fmt.Errorf("unknown format %q: must be one of %s", reviewFormatFlag, strings.Join(reviewFormats, ", "))
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	var (
+		diff          string
+		before, after fileReader
+	)
+	if reviewRangeFlag != "" {
+		from, to := parseRange(reviewRangeFlag)
+		if diff, err = git.GetRangeDiff(from, to); err != nil {
+			return err
+		}
+		before, after = atRevision(from), atRevision(to)
+	} else {
+		if diff, err = git.GetDiff(true); err != nil {
+			return fmt.Errorf("failed to get diff: %w", err)
+		}
+		before, after = atRevision("HEAD"), atRevision("")
+	}
+	gate := cfg.ReviewFailOn != review.SeverityNone
+	if strings.TrimSpace(diff) == "" {
+		if gate {
+			// Nothing to block, as for a commit that only changes its message
+			terminal.ShowSuccess("No changes to review")
+			return nil
+		}
+		return fmt.Errorf("no changes to review")
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return reviewFailed(cfg, fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	// The spinner would end up in the findings when stdout is redirected
+	stopSpinner := func() {}
+	if reviewOutputFlag != "" || terminal.IsOutputTerminal() {
+		stopSpinner = terminal.ShowSpinner("Reviewing changes...")
+	}
+	findings, res, err := llm.ReviewChanges(provider, diff, apiHints(apiChanges(diff, before, after), ""))
+	stopSpinner()
+	if err != nil {
+		return reviewFailed(cfg, fmt.Errorf("failed to review changes: %w", err))
+	}
+	review.Sort(findings)
+
+	if err = writeFindings(findings, diff, res.Stats); err != nil {
+		return err
+	}
+
+	if blocking := review.Blocking(findings, cfg.ReviewFailOn); len(blocking) > 0 {
+		return fmt.Errorf("%d findings at or above %s severity", len(blocking), cfg.ReviewFailOn)
+	}
+	return nil
+}
+
+// reviewFailed handles a review that could not be run. A gate fails open
+// with a warning, so that an unreachable provider does not block commits,
+// unless review_fail_on_error asks it to fail closed.
+func reviewFailed(cfg *config.Config, err error) error {
+	if cfg.ReviewFailOn == review.SeverityNone || cfg.ReviewFailOnError {
+		return err
+	}
+	terminal.WarningColor.Fprintf(os.Stderr, "! %s; skipping the review (use --fail-on-error to fail instead)\n", err)
+	return nil
+}
+
+type reviewOutput struct {
+	Findings  []llm.Finding `json:"findings"`
+	Usage     llm.Usage     `json:"usage"`
+	LatencyMS int64         `json:"latency_ms"`
+	// Cost is null when the model's price is unknown
+	Cost *float64 `json:"cost"`
+}
+
+// writeFindings prints the findings in the requested format, or writes them
+// to the --output file
+func writeFindings(findings []llm.Finding, diff string, stats llm.Stats) error {
+	var data []byte
+	switch reviewFormatFlag {
+	case "json":
+		out := reviewOutput{Findings: findings, Usage: stats.Usage, LatencyMS: stats.Latency.Milliseconds()}
+		if stats.CostKnown {
+			out.Cost = &stats.Cost
+		}
+		var err error
+		if data, err = json.MarshalIndent(out, "", "  "); err != nil {
+			return fmt.Errorf("failed to format findings: %w", err)
+		}
+	case "sarif":
+		var err error
+		if data, err = review.SARIF(findings, version.CtlVersion); err != nil {
+			return err
+		}
+	default:
+		if reviewOutputFlag == "" {
+			showFindings(findings, git.ParsePatch(diff))
+			showUsage(stats)
+			return nil
+		}
+		data = []byte(findingsText(findings))
+	}
+
+	if reviewOutputFlag == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	showUsage(stats)
+	if err := os.WriteFile(reviewOutputFlag, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write findings: %w", err)
+	}
+	terminal.ShowSuccess(fmt.Sprintf("Wrote %d findings to %s", len(findings), reviewOutputFlag))
+	return nil
+}
+
+// showFindings prints each finding below the diff line it refers to, grouped
+// by file and hunk. Findings outside the diff are listed with their file.
+func showFindings(findings []llm.Finding, hunks []git.Hunk) {
+	if len(findings) == 0 {
+		terminal.ShowSuccess("No findings")
+		return
+	}
+
+	file, hunk := "", -1
+	for _, f := range findings {
+		if f.File != file {
+			file, hunk = f.File, -1
+			terminal.TitleColor.Printf("\n%s\n", f.File)
+		}
+
+		h, text, ok := anchor(f, hunks)
+		if ok && h != hunk {
+			hunk = h
+			header, _, _ := strings.Cut(hunks[h].Body, "\n")
+			terminal.DividerColor.Printf("  %s\n", header)
+		}
+		switch {
+		case ok && strings.HasPrefix(text, "+"):
+			terminal.DiffAddColor.Printf("  %5d %s\n", f.Line, text)
+		case ok:
+			terminal.BodyColor.Printf("  %5d %s\n", f.Line, text)
+		case f.Line > 0:
+			terminal.BodyColor.Printf("  line %d\n", f.Line)
+		}
+
+		severityColor(f.Severity).Printf("        ▲ %s", strings.ToUpper(f.Severity))
+		terminal.BodyColor.Printf(" [%s] %s\n", f.Category, f.Message)
+	}
+	fmt.Println()
+}
+
+// anchor finds the hunk that shows the line of a finding and that line of the
+// diff, including its marker
+func anchor(f llm.Finding, hunks []git.Hunk) (int, string, bool) {
+	if f.Line == 0 {
+		return 0, "", false
+	}
+
+	for i, h := range hunks {
+		start, count := h.NewRange()
+		if h.File != f.File || f.Line < start || f.Line >= start+count {
+			continue
+		}
+
+		line := start
+		_, body, _ := strings.Cut(h.Body, "\n")
+		for _, l := range strings.Split(body, "\n") {
+			if strings.HasPrefix(l, "-") || strings.HasPrefix(l, `\`) {
+				continue
+			}
+			if line == f.Line {
+				return i, l, true
+			}
+			line++
+		}
+	}
+	return 0, "", false
+}
+
+func severityColor(severity string) *color.Color {
+	switch severity {
+	case "critical", "high":
+		return terminal.ErrorColor
+	case "medium":
+		return terminal.WarningColor
+	default:
+		return terminal.OptionColor
+	}
+}
+
+// findingsText formats findings as plain "file:line: severity [category] message" lines
+func findingsText(findings []llm.Finding) string {
+	var b strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&b, "%s:%d: %s [%s] %s\n", f.File, f.Line, f.Severity, f.Category, f.Message)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func reviewHookCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	threshold := cfg.ReviewFailOn
+	if threshold == review.SeverityNone {
+		threshold = "high"
+	}
+
+	dir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "pre-commit")
+	if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) && !reviewForceFlag {
+		return fmt.Errorf("%s already exists; use --force to replace it", path)
+	}
+
+	command := "zeusctl review --staged --fail-on " + threshold
+	if cfg.ReviewFailOnError {
+		command += " --fail-on-error"
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s\n", hookMarker, command)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err = os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of a replaced hook
+	if err = os.Chmod(path, 0o755); err != nil {
+		return fmt.Errorf("failed to make hook executable: %w", err)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Installed %s, which blocks commits with %s or more severe findings", path, threshold))
+	return nil
+}
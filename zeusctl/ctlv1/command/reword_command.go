@@ -0,0 +1,142 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var rewordForceFlag bool
+
+func NewRewordCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reword <rev>",
+		Short: "Suggest a new message for an earlier commit and rewrite it",
+		Long: `Suggest a new message for an earlier commit based on its diff and rewrite
+the commit with the chosen message. The commits after it are replayed with a
+non-interactive rebase, so their hashes change too.
+
+Rewording a commit that is already on a remote-tracking branch is refused
+unless --force is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: rewordCommandFunc,
+	}
+
+	// Flags override the matching config keys only when set, as for suggest
+	cmd.Flags().Bool("body", false, "Include detailed body text in suggestions")
+	cmd.Flags().Bool("edit", false, "Open the selected message in default editor")
+	addTrailerFlags(cmd)
+	addSigningFlags(cmd)
+	cmd.Flags().Bool("dry-run", false, "Display message suggestions but don't rewrite the commit")
+	cmd.Flags().String("style", "conventional", "Specify commit style (e.g., conventional, simple)")
+	cmd.Flags().Bool("no-cache", false, "Always generate fresh suggestions instead of reusing cached ones")
+	cmd.Flags().BoolVar(&rewordForceFlag, "force", false, "Reword the commit even if it has been pushed")
+
+	return cmd
+}
+
+func rewordCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	commit, err := git.ResolveCommit(args[0])
+	if err != nil {
+		return err
+	}
+
+	ancestor, err := git.IsAncestor(commit, "HEAD")
+	if err != nil {
+		return err
+	}
+	if !ancestor {
+		return fmt.Errorf("%s is not part of the current branch", args[0])
+	}
+
+	parents, err := git.CommitParents(commit)
+	if err != nil {
+		return err
+	}
+	if len(parents) > 1 {
+		return fmt.Errorf("%s is a merge commit and cannot be reworded", args[0])
+	}
+
+	pushed, err := git.IsPushed(commit)
+	if err != nil {
+		return err
+	}
+	if pushed && !rewordForceFlag {
+		return fmt.Errorf("%s has already been pushed; rewording it rewrites published history. Use --force to do it anyway", args[0])
+	}
+
+	trailers, err := commitTrailers(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	ticketID, err := branchTicket(cfg)
+	if err != nil {
+		return err
+	}
+
+	diff, err := git.GetCommitDiff(commit)
+	if err != nil {
+		return err
+	}
+	api := apiChanges(diff, atRevision(commit+"^"), atRevision(commit))
+	scopes, err := changeScopes(cfg, diffPaths(diff))
+	if err != nil {
+		return err
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	cache, err := suggestionCache(noCache)
+	if err != nil {
+		return err
+	}
+
+	hints := append(ticketHints(ticketID), scopeHints(scopes)...)
+	hints = append(hints, apiHints(api, cfg.DefaultStyle)...)
+	message, err := selectMessage(provider, cfg, diff, hints, cache)
+	if err != nil {
+		return err
+	}
+	message = applyScopes(message, scopes)
+	message = markBreaking(message, api, cfg)
+	if message, err = placeTicket(message, ticketID, cfg); err != nil {
+		return err
+	}
+	if message, err = git.AddTrailers(message, trailers); err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		terminal.ShowSuccess(fmt.Sprintf("Dry run - would reword %.7s to:", commit))
+		fmt.Println(message)
+		return nil
+	}
+
+	if err = git.Reword(commit, message, commitOptions(cfg)); err != nil {
+		return fmt.Errorf("reword failed: %w", err)
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Reworded %.7s", commit))
+	return nil
+}
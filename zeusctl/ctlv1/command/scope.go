@@ -0,0 +1,63 @@
+package command
+
+import (
+	"os"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/scope"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+// changeScopes returns the scopes inferred for the files, relative to the
+// repository root, or nil unless the conventional style is used
+func changeScopes(cfg *config.Config, files []string) ([]string, error) {
+	if cfg.DefaultStyle != "conventional" || (len(cfg.Scopes.Rules) == 0 && !cfg.Scopes.Detect) {
+		return nil, nil
+	}
+
+	rules, err := scope.ParseRules(cfg.Scopes.Rules)
+	if err != nil {
+		return nil, err
+	}
+	root, err := git.RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	return scope.Infer(root, files, rules, cfg.Scopes.Detect), nil
+}
+
+// diffPaths returns the paths of the files diff touches, using the old path
+// of deleted files
+func diffPaths(diff string) []string {
+	var paths []string
+	for _, f := range git.DiffFiles(diff) {
+		switch {
+		case f.NewPath != "":
+			paths = append(paths, f.NewPath)
+		case f.OldPath != "":
+			paths = append(paths, f.OldPath)
+		}
+	}
+	return paths
+}
+
+// scopeHints returns the prompt hints for the inferred scopes, if any
+func scopeHints(scopes []string) []string {
+	if len(scopes) == 0 {
+		return nil
+	}
+	return []string{scope.Hint(scopes)}
+}
+
+// applyScopes checks the scope of message against the inferred scopes,
+// filling in a missing one when there is only one and warning about unknown
+// ones without changing them
+func applyScopes(message string, scopes []string) string {
+	fixed, err := scope.Apply(message, scopes)
+	if err != nil {
+		// stderr keeps the warning out of the message printed by --dry-run
+		terminal.WarningColor.Fprintf(os.Stderr, "! %s\n", err)
+	}
+	return fixed
+}
@@ -0,0 +1,285 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+func NewSplitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split staged changes into several atomic commits",
+		Long: `Ask the model to group the hunks of the staged diff into logical commits,
+review and adjust the proposed grouping and messages, then create one commit
+per group. Only the index is touched; the working tree is left as it is.
+
+If creating any of the commits fails, the branch and the staged changes are
+restored to how they were before.`,
+		Args: cobra.NoArgs,
+		RunE: splitCommandFunc,
+	}
+
+	// Flags override the matching config keys only when set, as for suggest
+	cmd.Flags().Bool("body", false, "Include detailed body text in the messages")
+	addSigningFlags(cmd)
+	addTrailerFlags(cmd)
+	cmd.Flags().Bool("dry-run", false, "Show the proposed commits but don't create them")
+	cmd.Flags().String("style", "conventional", "Specify commit style (e.g., conventional, simple)")
+
+	return cmd
+}
+
+func splitCommandFunc(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	patch, err := git.GetStagedPatch()
+	if err != nil {
+		return err
+	}
+	hunks := git.ParsePatch(patch)
+	if len(hunks) == 0 {
+		return fmt.Errorf("no staged changes to split")
+	}
+	if len(hunks) == 1 {
+		return fmt.Errorf("the staged changes consist of a single hunk; use `zeusctl suggest` instead")
+	}
+
+	trailers, err := commitTrailers(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	ticketID, err := branchTicket(cfg)
+	if err != nil {
+		return err
+	}
+
+	provider, err := newProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+
+	texts := make([]string, len(hunks))
+	paths := make([]string, len(hunks))
+	for i, h := range hunks {
+		texts[i] = hunkText(h)
+		paths[i] = h.File
+	}
+	scopes, err := changeScopes(cfg, paths)
+	if err != nil {
+		return err
+	}
+
+	stopSpinner := terminal.ShowSpinner(fmt.Sprintf("Grouping %d hunks into commits...", len(hunks)))
+	groups, res, err := llm.SplitChanges(provider, texts, cfg.IncludeBody, cfg.DefaultStyle, append(ticketHints(ticketID), scopeHints(scopes)...))
+	stopSpinner()
+	if err != nil {
+		return fmt.Errorf("failed to split changes: %w", err)
+	}
+	showUsage(res.Stats)
+
+	groups, err = adjustGroups(groups, hunks, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		terminal.ShowSuccess(fmt.Sprintf("Dry run - would create %d commits", len(groups)))
+		return nil
+	}
+
+	for i := range groups {
+		// Each commit is checked against the scopes of its own hunks
+		var groupPaths []string
+		for _, h := range groups[i].Hunks {
+			groupPaths = append(groupPaths, hunks[h].File)
+		}
+		groupScopes, err := changeScopes(cfg, groupPaths)
+		if err != nil {
+			return err
+		}
+		groups[i].Message = applyScopes(groups[i].Message, groupScopes)
+		if groups[i].Message, err = placeTicket(groups[i].Message, ticketID, cfg); err != nil {
+			return err
+		}
+		if groups[i].Message, err = git.AddTrailers(groups[i].Message, trailers); err != nil {
+			return err
+		}
+	}
+
+	if err = commitGroups(groups, hunks, commitOptions(cfg)); err != nil {
+		return err
+	}
+
+	terminal.ShowSuccess(fmt.Sprintf("Created %d commits", len(groups)))
+	return nil
+}
+
+// hunkText renders a hunk for the prompt, leaving out binary patch data
+func hunkText(h git.Hunk) string {
+	header, _, binary := strings.Cut(h.Header, "GIT binary patch")
+	if binary {
+		header += "(binary file)\n"
+	}
+	return header + h.Body
+}
+
+// adjustGroups shows the proposed commits and lets the user move hunks
+// between them and edit messages until they accept
+func adjustGroups(groups []llm.CommitGroup, hunks []git.Hunk, cfg *config.Config) ([]llm.CommitGroup, error) {
+	for {
+		printGroups(groups, hunks)
+		if cfg.DryRun {
+			return groups, nil
+		}
+
+		input, err := terminal.Prompt("\n  Select an option (a/m/e/q)", "a")
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(input)
+
+		switch fields[0] {
+		case "a":
+			if i := missingMessage(groups); i >= 0 {
+				terminal.ShowError(fmt.Sprintf("Commit %d has no message; use e %d to write one", i+1, i+1))
+				continue
+			}
+			return groups, nil
+
+		case "m":
+			if len(fields) != 3 {
+				terminal.ShowError("Usage: m <hunk> <commit>")
+				continue
+			}
+			hunk, hunkErr := strconv.Atoi(fields[1])
+			target, targetErr := strconv.Atoi(fields[2])
+			if hunkErr != nil || targetErr != nil || hunk < 1 || hunk > len(hunks) || target < 1 || target > len(groups)+1 {
+				terminal.ShowError("Invalid hunk or commit number")
+				continue
+			}
+			groups = moveHunk(groups, hunk-1, target-1)
+
+		case "e":
+			n := 0
+			if len(fields) == 2 {
+				n, _ = strconv.Atoi(fields[1])
+			}
+			if n < 1 || n > len(groups) {
+				terminal.ShowError("Usage: e <commit>")
+				continue
+			}
+			message, editErr := terminal.EditMessage(groups[n-1].Message, cfg.IncludeBody, cfg.Editor)
+			if editErr != nil {
+				return nil, fmt.Errorf("failed to edit message: %w", editErr)
+			}
+			groups[n-1].Message = message
+
+		case "q":
+			return nil, errors.New("split cancelled, nothing was committed")
+
+		default:
+			terminal.ShowError("Invalid selection. Please choose a, m, e or q")
+		}
+	}
+}
+
+// moveHunk moves a hunk to the group at target, starting a new group when
+// target is one past the last, and drops groups left empty
+func moveHunk(groups []llm.CommitGroup, hunk, target int) []llm.CommitGroup {
+	if target == len(groups) {
+		groups = append(groups, llm.CommitGroup{})
+	}
+
+	for i := range groups {
+		kept := groups[i].Hunks[:0]
+		for _, h := range groups[i].Hunks {
+			if h != hunk {
+				kept = append(kept, h)
+			}
+		}
+		groups[i].Hunks = kept
+	}
+	groups[target].Hunks = append(groups[target].Hunks, hunk)
+
+	nonEmpty := groups[:0]
+	for _, g := range groups {
+		if len(g.Hunks) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
+
+func missingMessage(groups []llm.CommitGroup) int {
+	for i, g := range groups {
+		if strings.TrimSpace(g.Message) == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func printGroups(groups []llm.CommitGroup, hunks []git.Hunk) {
+	terminal.DividerColor.Println("\n┌───────────────────────────────────────────────────────┐")
+	terminal.TitleColor.Println("  PROPOSED COMMITS")
+	terminal.DividerColor.Println("├───────────────────────────────────────────────────────┤")
+
+	for i, g := range groups {
+		title, body, _ := strings.Cut(g.Message, "\n\n")
+		if title == "" {
+			title = "(no message)"
+		}
+		terminal.TitleColor.Printf("  %d. %s\n", i+1, title)
+		if body != "" {
+			terminal.BodyColor.Printf("     %s\n", strings.ReplaceAll(body, "\n", "\n     "))
+		}
+		for _, h := range g.Hunks {
+			terminal.OptionColor.Printf("       [%d] %s\n", h+1, hunks[h].Summary())
+		}
+	}
+
+	terminal.DividerColor.Println("├───────────────────────────────────────────────────────┤")
+	terminal.OptionColor.Println("  a - Accept and create the commits")
+	terminal.OptionColor.Println("  m <hunk> <commit> - Move a hunk (use the next number for a new commit)")
+	terminal.OptionColor.Println("  e <commit> - Edit a commit message")
+	terminal.OptionColor.Println("  q - Quit without committing")
+	terminal.DividerColor.Println("└───────────────────────────────────────────────────────┘")
+}
+
+// commitGroups creates one commit per group by staging only its hunks. On
+// failure the branch and the index are restored to their original state.
+func commitGroups(groups []llm.CommitGroup, hunks []git.Hunk, opts git.CommitOptions) error {
+	commits := make([]git.HunkCommit, len(groups))
+	for i, g := range groups {
+		commits[i].Message = g.Message
+		for _, h := range g.Hunks {
+			commits[i].Hunks = append(commits[i].Hunks, hunks[h])
+		}
+	}
+
+	err := git.CommitHunks(commits, opts)
+	if err != nil && !errors.Is(err, git.ErrRestoreFailed) {
+		terminal.ShowWarning("Restored the branch and staged changes")
+	}
+	return err
+}
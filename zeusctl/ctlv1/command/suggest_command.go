@@ -1,8 +1,10 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -12,28 +14,29 @@ import (
 	"github.com/amosehiguese/zeus-ai/internal/terminal"
 )
 
-var (
-	bodyFlag      bool
-	editFlag      bool
-	signFlag      bool
-	dryRunFlag    bool
-	autoStageFlag bool
-)
-
 func NewSuggestCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "suggest",
 		Short: "Suggest commit messages based on git diff",
-		Long:  `Suggest commit messages based on staged changes (or unstaged if nothing is staged)`,
-		RunE:  suggestCommandFunc,
+		Long: `Suggest commit messages based on staged changes (or unstaged if nothing is staged).
+
+With --amend, suggestions are based on the changes of the last commit plus
+anything staged, and the chosen message amends that commit.`,
+		RunE: suggestCommandFunc,
 	}
 
-	cmd.Flags().BoolVar(&bodyFlag, "body", false, "Include detailed body text in suggestions")
-	cmd.Flags().BoolVar(&editFlag, "edit", false, "Open the selected message in default editor")
-	cmd.Flags().BoolVar(&signFlag, "sign", false, "Sign the commit message")
-	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Display message suggestions but don't run commit")
-	cmd.Flags().BoolVar(&autoStageFlag, "auto-stage", false, "Automatically stage all changes")
-	cmd.Flags().StringVar(&styleFlag, "style", "conventional", "Specify commit style (e.g., conventional, simple)")
+	// Flags override the matching config keys (include_body, always_edit,
+	// signoff, gpg_sign, dry_run, auto_stage, default_style) only when set
+	cmd.Flags().Bool("body", false, "Include detailed body text in suggestions")
+	cmd.Flags().Bool("edit", false, "Open the selected message in default editor")
+	addSigningFlags(cmd)
+	addTrailerFlags(cmd)
+	cmd.Flags().Bool("dry-run", false, "Display message suggestions but don't run commit")
+	cmd.Flags().Bool("auto-stage", false, "Automatically stage all changes")
+	cmd.Flags().String("style", "conventional", "Specify commit style (e.g., conventional, simple)")
+	cmd.Flags().Bool("no-cache", false, "Always generate fresh suggestions instead of reusing cached ones")
+	cmd.Flags().Bool("json", false, "Print the suggestions with token usage and cost as JSON instead of committing")
+	cmd.Flags().Bool("amend", false, "Suggest a new message for the last commit and amend it, including any staged changes")
 
 	return cmd
 }
@@ -44,6 +47,9 @@ func suggestCommandFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if err = cfg.ApplyFlags(cmd.Flags()); err != nil {
+		return err
+	}
 
 	// Check if git repo
 	if !git.IsGitRepository() {
@@ -51,103 +57,306 @@ func suggestCommandFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	// Auto-stage if flag is set
-	if autoStageFlag {
+	if cfg.AutoStage {
 		if err = git.StageAllChanges(); err != nil {
 			return fmt.Errorf("failed to stage changes: %w", err)
 		}
 	}
 
-	// Get diff
-	diff, err := git.GetDiff(true) // Get staged diff first
+	amend, _ := cmd.Flags().GetBool("amend")
+
+	trailers, err := commitTrailers(cmd, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get diff: %w", err)
+		return err
 	}
 
-	// If no staged changes, check if there are unstaged changes
-	if diff == "" {
-		terminal.ShowWarning("No staged changes found.")
-		var unstaged bool
-		unstaged, err = git.HasUnstagedChanges()
-		if err != nil {
-			return fmt.Errorf("failed to check for unstaged changes: %w", err)
-		}
-
-		if unstaged {
-			var shouldUseUnstaged bool
-			shouldUseUnstaged, err = terminal.Confirm("Would you like to use unstaged changes instead?")
-			if err != nil {
-				return fmt.Errorf("failed to get confirmation: %w", err)
-			}
+	ticketID, err := branchTicket(cfg)
+	if err != nil {
+		return err
+	}
 
-			if shouldUseUnstaged {
-				diff, err = git.GetDiff(false)
-				if err != nil {
-					return fmt.Errorf("failed to get unstaged diff: %w", err)
-				}
-			} else {
-				return fmt.Errorf("no changes to commit")
-			}
-		} else {
-			return fmt.Errorf("no changes to commit")
+	// Get diff, with the sides it compares for the API analysis
+	var (
+		diff          string
+		before, after fileReader
+	)
+	if amend {
+		diff, err = git.GetAmendDiff()
+		before, after = atRevision("HEAD^"), atRevision("")
+	} else {
+		var staged bool
+		diff, staged, err = getCommitDiff()
+		before, after = atRevision("HEAD"), atRevision("")
+		if !staged {
+			before, after = atRevision(""), inWorkTree()
 		}
 	}
+	if err != nil {
+		return err
+	}
+	api := apiChanges(diff, before, after)
+	scopes, err := changeScopes(cfg, diffPaths(diff))
+	if err != nil {
+		return err
+	}
+	hints := append(ticketHints(ticketID), scopeHints(scopes)...)
+	hints = append(hints, apiHints(api, cfg.DefaultStyle)...)
 
 	// Create LLM provider
-	provider, err := llm.NewProvider(cfg.Provider, cfg.APIKey, cfg.Model)
+	provider, err := newProvider(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create LLM provider: %w", err)
 	}
 
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	cache, err := suggestionCache(noCache)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		result, cached, err := cachedSuggestions(provider, cfg, diff, hints, cache)
+		if err != nil {
+			return err
+		}
+		return printSuggestionsJSON(result, cached)
+	}
+
 	// Show diff stats
-	if stats, statErr := git.GetDiffStats(true); statErr == nil {
-		terminal.ShowDiffStats(stats)
+	if !amend {
+		if stats, statErr := git.GetDiffStats(true); statErr == nil {
+			terminal.ShowDiffStats(stats)
+		}
 	}
 
-	stopSpinner := terminal.ShowSpinner("Generating comit message suggestions...")
-	suggestions, err := provider.GenerateSuggestions(diff, bodyFlag, styleFlag)
-	stopSpinner()
+	commitMsg, err := selectMessage(provider, cfg, diff, hints, cache)
 	if err != nil {
-		log.Printf("Got an error while generating suggestions: %v", err)
 		return err
 	}
-	terminal.ShowSuccess("Generated 3 suggesions")
 
-	// Display suggestions
-	selectedIdx, err := terminal.DisplayAndSelectSuggestion(suggestions)
+	// The scope, breaking marker, ticket and trailers are enforced after the
+	// editor step so that they are never rewrapped
+	commitMsg = applyScopes(commitMsg, scopes)
+	commitMsg = markBreaking(commitMsg, api, cfg)
+	if commitMsg, err = placeTicket(commitMsg, ticketID, cfg); err != nil {
+		return err
+	}
+	if commitMsg, err = git.AddTrailers(commitMsg, trailers); err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		terminal.ShowSuccess("Dry run - would commit:")
+		fmt.Println(commitMsg)
+		return nil
+	}
+
+	// Perform the commit
+	if amend {
+		if err = git.Amend(commitMsg, commitOptions(cfg)); err != nil {
+			return fmt.Errorf("amend failed: %w", err)
+		}
+		terminal.ShowSuccess("Commit amended successfully")
+		return nil
+	}
+
+	if err := git.Commit(commitMsg, commitOptions(cfg)); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	terminal.ShowSuccess("Commit created successfully")
+	return nil
+}
+
+// getCommitDiff returns the staged diff, offering to use unstaged changes
+// when nothing is staged. It reports whether the diff is the staged one.
+func getCommitDiff() (string, bool, error) {
+	diff, err := git.GetDiff(true) // Get staged diff first
 	if err != nil {
-		return fmt.Errorf("failed to select suggestion: %w", err)
+		return "", false, fmt.Errorf("failed to get diff: %w", err)
+	}
+	if diff != "" {
+		return diff, true, nil
 	}
 
-	var commitMsg string
-	if selectedIdx == -1 {
-		// User wants to edit manually
-		commitMsg, err = terminal.EditMessage("", bodyFlag)
-		if err != nil {
-			return fmt.Errorf("failed to edit message: %w", err)
+	// If no staged changes, check if there are unstaged changes
+	terminal.ShowWarning("No staged changes found.")
+	unstaged, err := git.HasUnstagedChanges()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to check for unstaged changes: %w", err)
+	}
+	if !unstaged {
+		return "", false, fmt.Errorf("no changes to commit")
+	}
+
+	shouldUseUnstaged, err := terminal.Confirm("Would you like to use unstaged changes instead?")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !shouldUseUnstaged {
+		return "", false, fmt.Errorf("no changes to commit")
+	}
+
+	diff, err = git.GetDiff(false)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get unstaged diff: %w", err)
+	}
+	return diff, false, nil
+}
+
+// suggestionCache opens the suggestion cache, or returns nil when disabled
+func suggestionCache(disabled bool) (*llm.Cache, error) {
+	if disabled {
+		return nil, nil
+	}
+	return openCache()
+}
+
+// cachedSuggestions returns the cached suggestions for diff, generating and
+// caching new ones when there are none
+func cachedSuggestions(provider llm.Provider, cfg *config.Config, diff string, hints []string, cache *llm.Cache) (*llm.Result, bool, error) {
+	if cache != nil {
+		if entry, ok := cache.Get(llm.CacheKey(cfg.Provider, cfg.Model, diff, cfg.IncludeBody, cfg.DefaultStyle, hints)); ok {
+			return &llm.Result{Suggestions: entry.Suggestions, Stats: llm.Stats{CostKnown: true}}, true, nil
 		}
-	} else {
-		commitMsg = suggestions[selectedIdx]
+	}
 
-		// If edit flag is set, open the selected message in editor
-		if editFlag {
-			commitMsg, err = terminal.EditMessage(commitMsg, bodyFlag)
+	result, err := generateSuggestions(provider, cfg, diff, hints, cache)
+	return result, false, err
+}
+
+// selectMessage shows suggestions for diff, offering cached ones first when
+// available, and returns the message the user picked or wrote.
+func selectMessage(provider llm.Provider, cfg *config.Config, diff string, hints []string, cache *llm.Cache) (string, error) {
+	var (
+		result      *llm.Result
+		cached      bool
+		selectedIdx int
+		err         error
+	)
+
+	// Reuse suggestions already generated for the same diff and settings
+	if cache != nil {
+		if entry, ok := cache.Get(llm.CacheKey(cfg.Provider, cfg.Model, diff, cfg.IncludeBody, cfg.DefaultStyle, hints)); ok {
+			result, cached = &llm.Result{Suggestions: entry.Suggestions}, true
+			terminal.ShowSuccess(fmt.Sprintf("Using suggestions cached %s ago", time.Since(entry.CreatedAt).Round(time.Second)))
+		}
+	}
+
+	for {
+		if result == nil {
+			stopSpinner := terminal.ShowSpinner("Generating comit message suggestions...")
+			result, err = generateSuggestions(provider, cfg, diff, hints, cache)
+			stopSpinner()
 			if err != nil {
-				return fmt.Errorf("failed to edit message: %w", err)
+				log.Printf("Got an error while generating suggestions: %v", err)
+				return "", err
 			}
+			terminal.ShowSuccess("Generated 3 suggesions")
+			showUsage(result.Stats)
 		}
+
+		// Display suggestions
+		selectedIdx, err = terminal.DisplayAndSelectSuggestion(result.Suggestions, cached)
+		if err != nil {
+			return "", fmt.Errorf("failed to select suggestion: %w", err)
+		}
+		if selectedIdx != terminal.SelectRegenerate {
+			break
+		}
+		result, cached = nil, false
 	}
 
-	if dryRunFlag {
-		terminal.ShowSuccess("Dry run - would commit:")
-		fmt.Println(commitMsg)
-		return nil
+	if selectedIdx == terminal.SelectEdit {
+		// User wants to edit manually
+		commitMsg, err := terminal.EditMessage("", cfg.IncludeBody, cfg.Editor)
+		if err != nil {
+			return "", fmt.Errorf("failed to edit message: %w", err)
+		}
+		return commitMsg, nil
 	}
 
-	// Perform the commit
-	if err := git.Commit(commitMsg, signFlag); err != nil {
-		return fmt.Errorf("commit failed: %w", err)
+	commitMsg := result.Suggestions[selectedIdx]
+
+	// If edit flag is set, open the selected message in editor
+	if cfg.AlwaysEdit {
+		commitMsg, err = terminal.EditMessage(commitMsg, cfg.IncludeBody, cfg.Editor)
+		if err != nil {
+			return "", fmt.Errorf("failed to edit message: %w", err)
+		}
 	}
+	return commitMsg, nil
+}
 
-	terminal.ShowSuccess("Commit created successfully")
+// generateSuggestions asks the provider for suggestions and caches them
+func generateSuggestions(provider llm.Provider, cfg *config.Config, diff string, hints []string, cache *llm.Cache) (*llm.Result, error) {
+	result, err := provider.GenerateSuggestions(diff, cfg.IncludeBody, cfg.DefaultStyle, hints)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		key := llm.CacheKey(cfg.Provider, cfg.Model, diff, cfg.IncludeBody, cfg.DefaultStyle, hints)
+		if err = cache.Put(key, cfg.Provider, cfg.Model, result.Suggestions); err != nil {
+			terminal.ShowWarning(fmt.Sprintf("Failed to cache suggestions: %v", err))
+		}
+	}
+	return result, nil
+}
+
+// showUsage prints the tokens, latency and estimated cost of a request
+func showUsage(stats llm.Stats) {
+	terminal.BodyColor.Printf("  %s\n", usageSummary(stats))
+}
+
+func usageSummary(stats llm.Stats) string {
+	return fmt.Sprintf("%d prompt + %d completion tokens in %s, %s",
+		stats.Usage.PromptTokens,
+		stats.Usage.CompletionTokens,
+		stats.Latency.Round(10*time.Millisecond),
+		formatCost(stats.Cost, stats.CostKnown),
+	)
+}
+
+type suggestionsOutput struct {
+	Suggestions []string  `json:"suggestions"`
+	Cached      bool      `json:"cached"`
+	Usage       llm.Usage `json:"usage"`
+	LatencyMS   int64     `json:"latency_ms"`
+	// Cost is null when the model's price is unknown
+	Cost *float64 `json:"cost"`
+}
+
+func printSuggestionsJSON(result *llm.Result, cached bool) error {
+	out := suggestionsOutput{
+		Suggestions: result.Suggestions,
+		Cached:      cached,
+		Usage:       result.Usage,
+		LatencyMS:   result.Latency.Milliseconds(),
+	}
+	if result.CostKnown {
+		out.Cost = &result.Cost
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format suggestions: %w", err)
+	}
+
+	fmt.Println(string(data))
 	return nil
 }
+
+// addSigningFlags adds --signoff and --gpg-sign[=<key>], along with the
+// deprecated --sign, which only ever added a sign-off
+func addSigningFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("signoff", false, "Add a Signed-off-by trailer")
+	cmd.Flags().String("gpg-sign", "", "GPG or SSH sign the commit, optionally with the given key (overrides commit.gpgsign)")
+	cmd.Flags().Lookup("gpg-sign").NoOptDefVal = "true"
+	cmd.Flags().Bool("sign", false, "Add a Signed-off-by trailer")
+	_ = cmd.Flags().MarkDeprecated("sign", "it only adds a sign-off; use --signoff, or --gpg-sign to sign the commit")
+}
+
+func commitOptions(cfg *config.Config) git.CommitOptions {
+	return git.CommitOptions{Signoff: cfg.Signoff, GPGSign: cfg.GPGSign}
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"os"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+	"github.com/amosehiguese/zeus-ai/internal/ticket"
+)
+
+// branchTicket returns the ticket ID found in the current branch name, or an
+// empty string. It warns when the branch is one that must reference a ticket
+// but none of the patterns match it.
+func branchTicket(cfg *config.Config) (string, error) {
+	if len(cfg.Tickets.Patterns) == 0 && len(cfg.Tickets.RequiredBranches) == 0 {
+		return "", nil
+	}
+
+	branch, err := git.CurrentBranch()
+	if err != nil || branch == "" {
+		return "", err
+	}
+
+	id, err := ticket.Extract(branch, cfg.Tickets.Patterns)
+	if err != nil {
+		return "", err
+	}
+	if id == "" && ticket.Required(branch, cfg.Tickets.RequiredBranches) {
+		// stderr keeps the warning out of --json output and piped descriptions
+		terminal.WarningColor.Fprintf(os.Stderr, "! No ticket ID found in branch %q, which must reference one\n", branch)
+	}
+	return id, nil
+}
+
+// ticketHints returns the prompt hints for the ticket, if any
+func ticketHints(id string) []string {
+	if id == "" {
+		return nil
+	}
+	return []string{ticket.Hint(id)}
+}
+
+// placeTicket makes sure message references the ticket where tickets.placement
+// asks for it
+func placeTicket(message, id string, cfg *config.Config) (string, error) {
+	if id == "" {
+		return message, nil
+	}
+
+	switch cfg.Tickets.Placement {
+	case config.PlacementPrefix:
+		return ticket.Prefix(message, id), nil
+	case config.PlacementScope:
+		return ticket.Scope(message, id), nil
+	default:
+		return git.AddTrailers(message, []git.Trailer{{Key: cfg.Tickets.Trailer, Value: id}})
+	}
+}
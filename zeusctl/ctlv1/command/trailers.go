@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/config"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+)
+
+// addTrailerFlags adds the repeatable --trailer and --co-author flags
+func addTrailerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("trailer", nil, "Add a trailer to the message as key=value (repeatable)")
+	cmd.Flags().StringArray("co-author", nil, "Add a Co-authored-by trailer for a roster handle or \"Name <email>\" (repeatable)")
+}
+
+// commitTrailers collects the configured default trailers followed by those
+// given with --co-author and --trailer
+func commitTrailers(cmd *cobra.Command, cfg *config.Config) ([]git.Trailer, error) {
+	var trailers []git.Trailer
+	for _, s := range cfg.Trailers {
+		t, err := git.ParseTrailer(s)
+		if err != nil {
+			return nil, fmt.Errorf("trailers: %w", err)
+		}
+		trailers = append(trailers, t)
+	}
+
+	coAuthors, _ := cmd.Flags().GetStringArray("co-author")
+	if len(coAuthors) > 0 {
+		roster, err := loadRoster(cfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, query := range coAuthors {
+			identity, err := roster.Lookup(query)
+			if err != nil {
+				return nil, fmt.Errorf("--co-author: %w", err)
+			}
+			trailers = append(trailers, git.Trailer{Key: "Co-authored-by", Value: identity})
+		}
+	}
+
+	flags, _ := cmd.Flags().GetStringArray("trailer")
+	for _, s := range flags {
+		t, err := git.ParseTrailer(s)
+		if err != nil {
+			return nil, fmt.Errorf("--trailer: %w", err)
+		}
+		trailers = append(trailers, t)
+	}
+
+	return trailers, nil
+}
+
+// loadRoster reads the configured roster, resolving a relative path against
+// the repository root. Without a roster only full identities can be looked up.
+func loadRoster(cfg *config.Config) (config.Roster, error) {
+	if cfg.Roster == "" {
+		return config.Roster{}, nil
+	}
+
+	path := cfg.Roster
+	if !filepath.IsAbs(path) {
+		root, err := git.RepoRoot()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(root, path)
+	}
+	return config.LoadRoster(path)
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amosehiguese/zeus-ai/internal/llm"
+	"github.com/amosehiguese/zeus-ai/internal/terminal"
+)
+
+var (
+	usageByFlag    string
+	usageSinceFlag time.Duration
+)
+
+// usageGroups maps the values accepted by --by to the key each entry is grouped under
+var usageGroups = map[string]func(llm.LedgerEntry) string{
+	"day": func(e llm.LedgerEntry) string {
+		return e.Timestamp.Local().Format(time.DateOnly)
+	},
+	"repo": func(e llm.LedgerEntry) string {
+		if e.Repo == "" {
+			return "(none)"
+		}
+		return e.Repo
+	},
+	"model": func(e llm.LedgerEntry) string {
+		return e.Provider + "/" + e.Model
+	},
+}
+
+func NewUsageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show token usage and estimated spend",
+		Long: `Show the token usage and estimated spend of provider requests, grouped by
+day, repository or model. Every request is recorded in a local ledger;
+costs are estimated from a built-in price table and requests to models
+missing from it are marked with *.`,
+		Args: cobra.NoArgs,
+		RunE: usageCommandFunc,
+	}
+
+	cmd.Flags().StringVar(&usageByFlag, "by", "day", "Group usage by day, repo or model")
+	cmd.Flags().DurationVar(&usageSinceFlag, "since", 30*24*time.Hour, "Only include requests newer than this duration (0 for all)")
+
+	return cmd
+}
+
+func usageCommandFunc(cmd *cobra.Command, args []string) error {
+	key, ok := usageGroups[usageByFlag]
+	if !ok {
+		return fmt.Errorf("invalid value %q for --by: must be one of day, repo, model", usageByFlag)
+	}
+
+	ledger, err := openLedger()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ledger.Entries()
+	if err != nil {
+		return fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+
+	if usageSinceFlag > 0 {
+		cutoff := time.Now().Add(-usageSinceFlag)
+		recent := entries[:0]
+		for _, e := range entries {
+			if e.Timestamp.After(cutoff) {
+				recent = append(recent, e)
+			}
+		}
+		entries = recent
+	}
+
+	if len(entries) == 0 {
+		terminal.ShowWarning("No usage recorded")
+		return nil
+	}
+
+	totals := llm.Aggregate(entries, key)
+	overall := llm.Aggregate(entries, func(llm.LedgerEntry) string { return "TOTAL" })[0]
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tREQUESTS\tPROMPT TOKENS\tCOMPLETION TOKENS\tCOST\n", strings.ToUpper(usageByFlag))
+	for _, t := range append(totals, overall) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n",
+			t.Key,
+			t.Requests,
+			t.Usage.PromptTokens,
+			t.Usage.CompletionTokens,
+			formatTotalCost(t),
+		)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	if overall.Unpriced > 0 {
+		fmt.Printf("\n* %d request(s) used models with unknown pricing and are not included in the cost\n", overall.Unpriced)
+	}
+	return nil
+}
+
+// formatCost formats an estimated cost in US dollars
+func formatCost(cost float64, known bool) string {
+	if !known {
+		return "cost unknown"
+	}
+	return fmt.Sprintf("$%.4f", cost)
+}
+
+func formatTotalCost(t llm.UsageTotal) string {
+	if t.Unpriced > 0 {
+		return fmt.Sprintf("$%.4f*", t.Cost)
+	}
+	return fmt.Sprintf("$%.4f", t.Cost)
+}
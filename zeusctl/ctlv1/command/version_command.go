@@ -1,21 +1,103 @@
 package command
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/amosehiguese/zeus-ai/api/version"
+	"github.com/amosehiguese/zeus-ai/internal/changelog"
+	"github.com/amosehiguese/zeus-ai/internal/git"
+	"github.com/amosehiguese/zeus-ai/internal/release"
 	"github.com/amosehiguese/zeus-ai/internal/terminal"
 )
 
+var versionTagFlag bool
+
 func NewVersionCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number of zeus-ai",
 		Run:   versionCommandFunc,
 	}
+
+	nextCmd := &cobra.Command{
+		Use:   "next",
+		Short: "Recommend the next semantic version of the repository",
+		Long: `Recommend the next semantic version from the Conventional Commits messages
+since the latest version tag reachable from HEAD, such as v1.2.3.
+
+Breaking changes bump the major version (the minor version before 1.0.0),
+features bump the minor version and any other commits bump the patch version.
+With --tag, an annotated tag is created at HEAD with the changelog of the
+included commits as its message.`,
+		Args: cobra.NoArgs,
+		RunE: versionNextCommandFunc,
+	}
+	nextCmd.Flags().BoolVar(&versionTagFlag, "tag", false, "Create an annotated tag for the recommended version")
+
+	cmd.AddCommand(nextCmd)
+	return cmd
 }
 
 func versionCommandFunc(cmd *cobra.Command, args []string) {
 	terminal.TitleColor.Println("zeusctl version:", version.CtlVersion)
 	terminal.TitleColor.Println("zeus-ai version:", version.Version)
 }
+
+func versionNextCommandFunc(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	tags, err := git.MergedTags("HEAD")
+	if err != nil {
+		return err
+	}
+	current, tag, ok := release.Latest(tags)
+	if !ok {
+		// The first release starts from 0.0.0 and includes every commit
+		current = release.Version{Prefix: "v"}
+	}
+
+	log, err := git.Log(tag, "HEAD")
+	if err != nil {
+		return err
+	}
+	if len(log) == 0 {
+		return fmt.Errorf("no commits since %s", tag)
+	}
+
+	entries := make([]changelog.Entry, len(log))
+	for i, e := range log {
+		entries[i] = changelog.NewEntry(e.Commit, e.Message())
+	}
+	rec := release.Recommend(current, entries)
+
+	if ok {
+		terminal.BodyColor.Printf("Current version: %s\n", tag)
+	} else {
+		terminal.BodyColor.Println("Current version: none (no version tags)")
+	}
+	terminal.TitleColor.Printf("Next version:    %s (%s)\n", rec.Next, rec.Bump)
+	fmt.Println()
+	for _, reason := range rec.Reasons {
+		terminal.BodyColor.Printf("  - %s\n", reason)
+	}
+
+	if !versionTagFlag {
+		return nil
+	}
+
+	message, err := changelog.Changelog{Title: rec.Next.String(), Date: time.Now(), Entries: entries}.Render(changelog.FormatMarkdown)
+	if err != nil {
+		return err
+	}
+	if err = git.CreateTag(rec.Next.String(), message); err != nil {
+		return err
+	}
+	fmt.Println()
+	terminal.ShowSuccess(fmt.Sprintf("Created tag %s", rec.Next))
+	return nil
+}
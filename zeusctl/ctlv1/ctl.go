@@ -6,6 +6,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/amosehiguese/zeus-ai/internal/config"
 	"github.com/amosehiguese/zeus-ai/internal/terminal"
 	"github.com/amosehiguese/zeus-ai/pkg/cobrautil"
 	"github.com/amosehiguese/zeus-ai/zeusctl/ctlv1/command"
@@ -25,16 +26,36 @@ var rootCmd = &cobra.Command{
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Disable colors if output isn't a terminal
 		color.NoColor = !isTerminal()
+		config.SelectProfile(profileFlag)
 	},
 	SilenceErrors: true,
 	SilenceUsage:  true,
 }
 
+var profileFlag string
+
 func init() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Configuration profile to use (overrides ZEUS_PROFILE)")
+
 	rootCmd.AddCommand(
 		command.NewInitCommand(),
 		command.NewVersionCommand(),
 		command.NewSuggestCommand(),
+		command.NewAuditCommand(),
+		command.NewConfigCommand(),
+		command.NewAuthCommand(),
+		command.NewDoctorCommand(),
+		command.NewModelsCommand(),
+		command.NewProviderCommand(),
+		command.NewUsageCommand(),
+		command.NewRewordCommand(),
+		command.NewSplitCommand(),
+		command.NewFixupCommand(),
+		command.NewBranchCommand(),
+		command.NewPRCommand(),
+		command.NewChangelogCommand(),
+		command.NewReviewCommand(),
+		command.NewExplainCommand(),
 	)
 }
 